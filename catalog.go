@@ -10,12 +10,14 @@ import (
 )
 
 type CatalogEntity struct {
-	EntityType string         `json:"entityType,omitempty"`
-	Id         string         `json:"id,omitempty"`
-	Tag        string         `json:"tag,omitempty"`
-	Path       []string       `json:"path,omitempty"`
-	Name       string         `json:"name,omitempty"`
-	Children   []CatalogChild `json:"children,omitempty"`
+	EntityType        string             `json:"entityType,omitempty"`
+	Id                string             `json:"id,omitempty"`
+	Tag               string             `json:"tag,omitempty"`
+	Path              []string           `json:"path,omitempty"`
+	Name              string             `json:"name,omitempty"`
+	Children          []CatalogChild     `json:"children,omitempty"`
+	AccessControlList *AccessControlList `json:"accessControlList,omitempty"`
+	Owner             *Owner             `json:"owner,omitempty"`
 }
 
 type CatalogEntitySummary struct {
@@ -155,7 +157,11 @@ func (c *Client) updateCatalogItem(id string, payload interface{}, result interf
 		return err
 	}
 	path := fmt.Sprintf("/api/v3/catalog/%s", url.QueryEscape(id))
-	return c.request("PUT", path, bytes.NewBuffer(body), result)
+	err = c.request("PUT", path, bytes.NewBuffer(body), result)
+	if isAlreadyExists(err) {
+		return &ConflictError{Id: id, Err: err}
+	}
+	return err
 }
 
 func (c *Client) DeleteCatalogItem(id string) error {
@@ -188,3 +194,43 @@ func (ce *CatalogChild) EnrichFields() {
 	}
 	log.Printf("Enriched Child: %#v", ce)
 }
+
+func quotePath(path []string) string {
+	elements := make([]string, len(path))
+	for i, e := range path {
+		elements[i] = `"` + strings.ReplaceAll(e, `"`, `""`) + `"`
+	}
+	return strings.Join(elements, ".")
+}
+
+func hasPathPrefix(path []string, prefix []string) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i, e := range prefix {
+		if path[i] != e {
+			return false
+		}
+	}
+	return true
+}
+
+// walkCatalog calls fn for every descendant of the container with the given
+// id, descending into folders and other containers depth first.
+func (c *Client) walkCatalog(id string, fn func(child CatalogChild) error) error {
+	entity, err := c.GetCatalogEntityById(id)
+	if err != nil {
+		return err
+	}
+	for _, child := range entity.Children {
+		if err := fn(child); err != nil {
+			return err
+		}
+		if child.Type == "CONTAINER" {
+			if err := c.walkCatalog(child.Id, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"sync"
 
 	"github.com/hashicorp/go-cleanhttp"
 )
@@ -19,13 +20,15 @@ type Client struct {
 	config  Config
 	baseUrl url.URL
 	client  *http.Client
+	authMu  sync.Mutex
 }
 
 type Config struct {
-	ApiKey   string
-	Username string
-	Password string
-	Client   *http.Client
+	ApiKey         string
+	Username       string
+	Password       string
+	Client         *http.Client
+	SecretProvider SecretProvider
 }
 
 // New creates a new Dremio client.
@@ -49,12 +52,8 @@ func NewClient(baseUrl string, cfg Config) (*Client, error) {
 }
 
 func (c *Client) request(method, requestPath string, body io.Reader, responseStruct interface{}) error {
-	if c.config.ApiKey == "" {
-		apikey, err := c.getApiKey(c.config.Username, c.config.Password)
-		if err != nil {
-			return err
-		}
-		c.config.ApiKey = apikey
+	if err := c.login(); err != nil {
+		return err
 	}
 
 	r, err := c.newRequest(method, requestPath, body)
@@ -78,7 +77,7 @@ func (c *Client) request(method, requestPath string, body io.Reader, responseStr
 	}
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("status: %d, body: %v", resp.StatusCode, string(bodyContents))
+		return &ApiError{StatusCode: resp.StatusCode, Body: string(bodyContents)}
 	}
 
 	if responseStruct == nil {
@@ -109,7 +108,7 @@ func (c *Client) newRequest(method, requestPath string, body io.Reader) (*http.R
 		if body == nil {
 			log.Printf("request (%s) to %s with no body data", method, url)
 		} else {
-			log.Printf("request (%s) to %s with body data: %s", method, url, body.(*bytes.Buffer))
+			log.Printf("request (%s) to %s with body data: %s", method, url, redactJson(body.(*bytes.Buffer).Bytes()))
 		}
 	}
 
@@ -119,6 +118,21 @@ func (c *Client) newRequest(method, requestPath string, body io.Reader) (*http.R
 	return req, err
 }
 
+// login fetches an API key on first use. It is safe for concurrent use.
+func (c *Client) login() error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	if c.config.ApiKey != "" {
+		return nil
+	}
+	apikey, err := c.getApiKey(c.config.Username, c.config.Password)
+	if err != nil {
+		return err
+	}
+	c.config.ApiKey = apikey
+	return nil
+}
+
 type authResponse struct {
 	Token   string `json:"token"`
 	Expires int    `json:"expires"`
@@ -0,0 +1,350 @@
+package dapi
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+type CopyTreeOptions struct {
+	RewriteSql      bool
+	CopyTags        bool
+	CopyWiki        bool
+	CopyReflections bool
+}
+
+type treeCopy struct {
+	client   *Client
+	srcPath  []string
+	dstPath  []string
+	opts     *CopyTreeOptions
+	datasets []*VirtualDataset
+}
+
+// CopyTree recreates the folders and virtual datasets found under srcPath
+// beneath dstPath. The destination and its space are created if they do not
+// exist yet, and folders that already exist in the destination are reused.
+func (c *Client) CopyTree(srcPath []string, dstPath []string, opts *CopyTreeOptions) error {
+	if opts == nil {
+		opts = &CopyTreeOptions{}
+	}
+	if hasPathPrefix(dstPath, srcPath) {
+		return errors.New("Copy destination is inside the copy source")
+	}
+	src, err := c.GetCatalogEntityByPath(srcPath)
+	if err != nil {
+		return err
+	}
+	if src.EntityType != "space" && src.EntityType != "folder" {
+		return errors.New("Copy source is not a space or folder")
+	}
+	dst, err := c.copyTreeRoot(dstPath)
+	if err != nil {
+		return err
+	}
+
+	t := &treeCopy{
+		client:  c,
+		srcPath: src.Path,
+		dstPath: dst.Path,
+		opts:    opts,
+	}
+	if err := t.copyCollaboration(src.Id, dst.Id, false); err != nil {
+		return err
+	}
+	if err := t.copyChildren(src); err != nil {
+		return err
+	}
+	return t.copyDatasets()
+}
+
+func (c *Client) copyTreeRoot(path []string) (*CatalogEntity, error) {
+	existing, err := c.GetCatalogEntityByPath(path)
+	if err == nil {
+		if existing.EntityType != "space" && existing.EntityType != "folder" {
+			return nil, errors.New("Copy destination is not a space or folder")
+		}
+		return existing, nil
+	}
+	if !IsNotFound(err) {
+		return nil, err
+	}
+	if _, err := c.GetCatalogEntityByPath(path[:1]); IsNotFound(err) {
+		space, err := c.NewSpace(&NewSpaceSpec{Name: path[0]})
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 1 {
+			return &space.CatalogEntity, nil
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	folder, err := c.NewFolderRecursive(&NewFolderSpec{Path: path})
+	if err != nil {
+		return nil, err
+	}
+	return &folder.CatalogEntity, nil
+}
+
+func (t *treeCopy) copyChildren(parent *CatalogEntity) error {
+	for _, child := range parent.Children {
+		switch {
+		case child.Type == "CONTAINER" && child.ContainerType == "FOLDER":
+			if err := t.copyFolder(child.Id); err != nil {
+				return err
+			}
+		case child.Type == "DATASET" && child.DatasetType == "VIRTUAL":
+			dataset, err := t.client.GetVirtualDataset(child.Id)
+			if err != nil {
+				return err
+			}
+			t.datasets = append(t.datasets, dataset)
+		}
+	}
+	return nil
+}
+
+func (t *treeCopy) copyFolder(id string) error {
+	src, err := t.client.GetCatalogEntityById(id)
+	if err != nil {
+		return err
+	}
+	dst, _, err := t.client.getOrCreateFolder(t.destination(src.Path))
+	if err != nil {
+		return err
+	}
+	if err := t.copyCollaboration(src.Id, dst.Id, false); err != nil {
+		return err
+	}
+	return t.copyChildren(src)
+}
+
+// copyDatasets creates the collected virtual datasets. Views may select from
+// other views in the same tree, so creation is retried in passes until every
+// dataset exists or a pass makes no progress.
+func (t *treeCopy) copyDatasets() error {
+	pending := t.datasets
+	for len(pending) > 0 {
+		var failed []*VirtualDataset
+		var lastErr error
+		for _, src := range pending {
+			dst, err := t.client.NewVirtualDataset(&NewVirtualDatasetSpec{
+				Path:       t.destination(src.Path),
+				Sql:        t.sql(src.Sql),
+				SqlContext: t.sqlContext(src.SqlContext),
+			})
+			if err != nil {
+				failed = append(failed, src)
+				lastErr = err
+				continue
+			}
+			if err := t.copyCollaboration(src.Id, dst.Id, true); err != nil {
+				return err
+			}
+			if t.opts.CopyReflections {
+				if err := t.copyReflections(src.Id, dst.Id); err != nil {
+					return err
+				}
+			}
+		}
+		if len(failed) == len(pending) {
+			return lastErr
+		}
+		pending = failed
+	}
+	return nil
+}
+
+func (t *treeCopy) copyCollaboration(srcId string, dstId string, tags bool) error {
+	if tags && t.opts.CopyTags {
+		srcTags, err := t.client.GetEntityTags(srcId)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if len(srcTags.Tags) > 0 {
+			if err := t.client.SetEntityTags(dstId, srcTags.Tags, ""); err != nil {
+				return err
+			}
+		}
+	}
+	if t.opts.CopyWiki {
+		wiki, err := t.client.GetEntityWiki(srcId)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if wiki.Text != "" {
+			if err := t.client.SetEntityWiki(dstId, wiki.Text, 0); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *treeCopy) copyReflections(srcId string, dstId string) error {
+	reflections, err := t.client.getDatasetReflections(srcId)
+	if err != nil {
+		return err
+	}
+	for _, r := range reflections {
+		switch r.Type {
+		case "RAW":
+			_, err = t.client.NewRawReflection(dstId, &RawReflectionSpec{
+				Name:                          r.Name,
+				Enabled:                       r.Enabled,
+				DisplayFields:                 r.DisplayFields,
+				DistributionFields:            r.DistributionFields,
+				PartitionFields:               r.PartitionFields,
+				SortFields:                    r.SortFields,
+				PartitionDistributionStrategy: r.PartitionDistributionStrategy,
+			})
+		case "AGGREGATION":
+			_, err = t.client.NewAggregationReflection(dstId, &AggregationReflectionSpec{
+				Name:                          r.Name,
+				Enabled:                       r.Enabled,
+				DimensionFields:               r.DimensionFields,
+				MeasureFields:                 r.MeasureFields,
+				DistributionFields:            r.DistributionFields,
+				PartitionFields:               r.PartitionFields,
+				SortFields:                    r.SortFields,
+				PartitionDistributionStrategy: r.PartitionDistributionStrategy,
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *treeCopy) destination(path []string) []string {
+	result := append([]string{}, t.dstPath...)
+	return append(result, path[len(t.srcPath):]...)
+}
+
+func (t *treeCopy) sql(sql string) string {
+	if !t.opts.RewriteSql {
+		return sql
+	}
+	return rewriteSqlPaths(sql, t.srcPath, t.dstPath)
+}
+
+// rewriteSqlPaths replaces references to datasets beneath src with the same
+// references beneath dst. Only whole path references are rewritten: each
+// element must be a complete identifier, quoted or not, the reference must
+// start at the beginning of the SQL or after whitespace, "(" or "," and it
+// must continue with a ".". String literals are left untouched.
+func rewriteSqlPaths(sql string, src []string, dst []string) string {
+	var sb strings.Builder
+	for i := 0; i < len(sql); {
+		if pathBoundary(sql, i) {
+			if n, quoted := matchSqlPath(sql[i:], src); n > 0 {
+				sb.WriteString(sqlPath(dst, quoted))
+				i += n
+				continue
+			}
+		}
+		switch sql[i] {
+		case '\'', '"':
+			n := quotedLength(sql[i:])
+			sb.WriteString(sql[i : i+n])
+			i += n
+		default:
+			sb.WriteByte(sql[i])
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// sqlPath formats path for SQL, quoting every element when quoted is set and
+// otherwise only the elements that are not plain identifiers.
+func sqlPath(path []string, quoted bool) string {
+	if quoted {
+		return quotePath(path)
+	}
+	elements := make([]string, len(path))
+	for i, e := range path {
+		if plainIdentifier(e) {
+			elements[i] = e
+		} else {
+			elements[i] = quotePath([]string{e})
+		}
+	}
+	return strings.Join(elements, ".")
+}
+
+func plainIdentifier(s string) bool {
+	for i, r := range s {
+		if r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return false
+	}
+	return s != ""
+}
+
+func pathBoundary(sql string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	switch sql[i-1] {
+	case ' ', '\t', '\n', '\r', '(', ',':
+		return true
+	}
+	return false
+}
+
+// matchSqlPath returns the length of the reference to path at the start of
+// sql, including the "." that follows it, or 0 when there is none. quoted
+// reports whether any element of the reference was quoted. Elements are
+// compared ignoring case, as Dremio resolves identifiers.
+func matchSqlPath(sql string, path []string) (n int, quoted bool) {
+	for _, e := range path {
+		rest := sql[n:]
+		q := quotePath([]string{e})
+		switch {
+		case hasPrefixFold(rest, q):
+			n += len(q)
+			quoted = true
+		case hasPrefixFold(rest, e):
+			n += len(e)
+		default:
+			return 0, false
+		}
+		if n >= len(sql) || sql[n] != '.' {
+			return 0, false
+		}
+		n++
+	}
+	return n - 1, quoted
+}
+
+func hasPrefixFold(s string, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// quotedLength returns the length of the quoted literal or identifier at the
+// start of sql, treating a doubled quote character as an escaped quote.
+func quotedLength(sql string) int {
+	q := sql[0]
+	for i := 1; i < len(sql); i++ {
+		if sql[i] != q {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == q {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(sql)
+}
+
+func (t *treeCopy) sqlContext(sqlContext []string) []string {
+	if !t.opts.RewriteSql || !hasPathPrefix(sqlContext, t.srcPath) {
+		return sqlContext
+	}
+	return t.destination(sqlContext)
+}
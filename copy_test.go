@@ -0,0 +1,91 @@
+package dapi
+
+import "testing"
+
+func TestRewriteSqlPaths(t *testing.T) {
+	tests := []struct {
+		name string
+		src  []string
+		dst  []string
+		sql  string
+		want string
+	}{
+		{
+			name: "short source name",
+			src:  []string{"a"},
+			dst:  []string{"cust1"},
+			sql:  "SELECT * FROM data.orders JOIN a.x",
+			want: "SELECT * FROM data.orders JOIN cust1.x",
+		},
+		{
+			name: "lookalike prefix",
+			src:  []string{"Sales"},
+			dst:  []string{"Cust"},
+			sql:  "SELECT * FROM Sales2.orders JOIN Sales.customers ON Sales2.id = Sales.id",
+			want: "SELECT * FROM Sales2.orders JOIN Cust.customers ON Sales2.id = Cust.id",
+		},
+		{
+			name: "lower case reference",
+			src:  []string{"Sales"},
+			dst:  []string{"Cust"},
+			sql:  `SELECT * FROM sales.orders JOIN "SALES"."customers" USING (id)`,
+			want: `SELECT * FROM Cust.orders JOIN "Cust"."customers" USING (id)`,
+		},
+		{
+			name: "quoted lookalike prefix",
+			src:  []string{"Sales"},
+			dst:  []string{"Cust"},
+			sql:  `SELECT * FROM "Sales2"."orders" JOIN "Sales"."customers"`,
+			want: `SELECT * FROM "Sales2"."orders" JOIN "Cust"."customers"`,
+		},
+		{
+			name: "nested path",
+			src:  []string{"Sales", "eu"},
+			dst:  []string{"Cust", "eu 2"},
+			sql:  `SELECT * FROM Sales.eu.orders, (SELECT * FROM "Sales"."eu"."items")`,
+			want: `SELECT * FROM Cust."eu 2".orders, (SELECT * FROM "Cust"."eu 2"."items")`,
+		},
+		{
+			name: "nested path not matching a sibling",
+			src:  []string{"Sales", "eu"},
+			dst:  []string{"Cust", "eu"},
+			sql:  "SELECT * FROM Sales.europe.orders",
+			want: "SELECT * FROM Sales.europe.orders",
+		},
+		{
+			name: "path without dataset",
+			src:  []string{"a"},
+			dst:  []string{"b"},
+			sql:  "SELECT a FROM x.a",
+			want: "SELECT a FROM x.a",
+		},
+		{
+			name: "string literal",
+			src:  []string{"a"},
+			dst:  []string{"b"},
+			sql:  "SELECT ' a.x', 'it''s a.x' FROM a.x",
+			want: "SELECT ' a.x', 'it''s a.x' FROM b.x",
+		},
+		{
+			name: "inside quoted identifier",
+			src:  []string{"a"},
+			dst:  []string{"b"},
+			sql:  `SELECT * FROM "x a.y"."z"`,
+			want: `SELECT * FROM "x a.y"."z"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rewriteSqlPaths(tt.sql, tt.src, tt.dst)
+			if got != tt.want {
+				t.Errorf("rewriteSqlPaths(%q) = %q, want %q", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotePath(t *testing.T) {
+	if got, want := quotePath([]string{"my space", `a"b`}), `"my space"."a""b"`; got != want {
+		t.Errorf("quotePath() = %s, want %s", got, want)
+	}
+}
@@ -38,6 +38,9 @@ type PhysicalDataset struct {
 	AccelerationRefreshPolicy *DatasetAccelerationRefreshPolicy `json:"accelerationRefreshPolicy,omitempty"`
 }
 
+// PhysicalDatasetFormat is the format of a promoted file or folder. The
+// "Extract Field Names" option of the Dremio UI is ExtractHeader; the API has
+// no separate extractFieldNames setting.
 type PhysicalDatasetFormat struct {
 	Type                    string `json:"type,omitempty"`
 	FieldDelimiter          string `json:"fieldDelimiter,omitempty"`
@@ -51,15 +54,17 @@ type PhysicalDatasetFormat struct {
 	AutoGenerateColumnNames bool   `json:"autoGenerateColumnNames,omitempty"`
 	SheetName               string `json:"sheetName,omitempty"`
 	HasMergedCells          bool   `json:"hasMergedCells,omitempty"`
+	AutoCorrectCorruptDates bool   `json:"autoCorrectCorruptDates,omitempty"`
+	IgnoreOtherFileFormats  bool   `json:"ignoreOtherFileFormats,omitempty"`
 }
 
 type DatasetAccelerationRefreshPolicy struct {
-	RefreshPeriodMs int    `json:"refreshPeriodMs,omitempty"`
-	GracePeriodMs   int    `json:"gracePeriodMs,omitempty"`
-	Method          string `json:"method,omitempty"`
-	RefreshField    string `json:"refreshField,omitempty"`
-	NeverExpire     bool   `json:"neverExpire,omitempty"`
-	NeverRefresh    bool   `json:"neverRefresh,omitempty"`
+	RefreshPeriodMs int64         `json:"refreshPeriodMs,omitempty"`
+	GracePeriodMs   int64         `json:"gracePeriodMs,omitempty"`
+	Method          RefreshMethod `json:"method,omitempty"`
+	RefreshField    string        `json:"refreshField,omitempty"`
+	NeverExpire     bool          `json:"neverExpire,omitempty"`
+	NeverRefresh    bool          `json:"neverRefresh,omitempty"`
 }
 
 func (c *Client) GetDataset(id string) (*Dataset, error) {
@@ -135,6 +140,7 @@ func (c *Client) NewVirtualDataset(spec *NewVirtualDatasetSpec) (*VirtualDataset
 }
 
 type UpdateVirtualDatasetSpec struct {
+	Tag        string
 	Sql        string
 	SqlContext []string
 }
@@ -144,11 +150,15 @@ func (c *Client) UpdateVirtualDataset(id string, spec *UpdateVirtualDatasetSpec)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkTag(id, spec.Tag, original.Tag); err != nil {
+		return nil, err
+	}
 	dataset := VirtualDataset{
 		Dataset:    original.Dataset,
 		Sql:        spec.Sql,
 		SqlContext: spec.SqlContext,
 	}
+	dataset.Tag = writeTag(spec.Tag, original.Tag)
 	result := new(VirtualDataset)
 	err = c.updateCatalogItem(id, dataset, result)
 	if err != nil {
@@ -158,6 +168,35 @@ func (c *Client) UpdateVirtualDataset(id string, spec *UpdateVirtualDatasetSpec)
 	return result, nil
 }
 
+const maxModifyAttempts = 5
+
+// ModifyVirtualDataset reads the dataset, applies modify to it and writes it
+// back. If the dataset is changed by someone else in between, the whole cycle
+// is retried up to maxModifyAttempts times.
+func (c *Client) ModifyVirtualDataset(id string, modify func(*VirtualDataset) error) (*VirtualDataset, error) {
+	var err error
+	for attempt := 0; attempt < maxModifyAttempts; attempt++ {
+		var dataset *VirtualDataset
+		dataset, err = c.GetVirtualDataset(id)
+		if err != nil {
+			return nil, err
+		}
+		if err = modify(dataset); err != nil {
+			return nil, err
+		}
+		result := new(VirtualDataset)
+		err = c.updateCatalogItem(id, dataset, result)
+		if err == nil {
+			result.EnrichFields()
+			return result, nil
+		}
+		if !IsConflict(err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
 type NewPhysicalDatasetSpec struct {
 	Path                      []string
 	Format                    *PhysicalDatasetFormat
@@ -169,6 +208,7 @@ func (c *Client) NewPhysicalDataset(fileId string, spec *NewPhysicalDatasetSpec)
 		Dataset: Dataset{
 			CatalogEntity: CatalogEntity{
 				EntityType: "dataset",
+				Id:         fileId,
 				Path:       spec.Path,
 			},
 			Type: "PHYSICAL_DATASET",
@@ -180,6 +220,12 @@ func (c *Client) NewPhysicalDataset(fileId string, spec *NewPhysicalDatasetSpec)
 	if err != nil {
 		return nil, err
 	}
+	if err := dataset.Format.Validate(); err != nil {
+		return nil, err
+	}
+	if err := dataset.AccelerationRefreshPolicy.Validate(); err != nil {
+		return nil, err
+	}
 	result := new(PhysicalDataset)
 	path := fmt.Sprintf("/api/v3/catalog/%s", url.QueryEscape(fileId))
 	err = c.request("POST", path, bytes.NewBuffer(body), result)
@@ -191,6 +237,7 @@ func (c *Client) NewPhysicalDataset(fileId string, spec *NewPhysicalDatasetSpec)
 }
 
 type UpdatePhysicalDatasetSpec struct {
+	Tag                       string
 	Format                    *PhysicalDatasetFormat
 	AccelerationRefreshPolicy *DatasetAccelerationRefreshPolicy
 }
@@ -200,11 +247,112 @@ func (c *Client) UpdatePhysicalDataset(id string, spec *UpdatePhysicalDatasetSpe
 	if err != nil {
 		return nil, err
 	}
+	if err := checkTag(id, spec.Tag, original.Tag); err != nil {
+		return nil, err
+	}
 	dataset := PhysicalDataset{
 		Dataset:                   original.Dataset,
 		Format:                    spec.Format,
 		AccelerationRefreshPolicy: spec.AccelerationRefreshPolicy,
 	}
+	dataset.Tag = writeTag(spec.Tag, original.Tag)
+	if err := dataset.Format.Validate(); err != nil {
+		return nil, err
+	}
+	if err := dataset.AccelerationRefreshPolicy.Validate(); err != nil {
+		return nil, err
+	}
+	result := new(PhysicalDataset)
+	err = c.updateCatalogItem(id, dataset, result)
+	if err != nil {
+		return nil, err
+	}
+	result.EnrichFields()
+	return result, nil
+}
+
+// PatchPhysicalDatasetSpec describes a partial physical dataset update. Nil
+// fields keep their current value; the options set in Format and
+// AccelerationRefreshPolicy are merged into the current values.
+type PatchPhysicalDatasetSpec struct {
+	Tag                       string
+	Format                    *PhysicalDatasetFormatPatch
+	AccelerationRefreshPolicy *DatasetAccelerationRefreshPolicyPatch
+}
+
+// PhysicalDatasetFormatPatch holds the format options to change. Nil fields
+// keep their current value, so options can also be set back to false or "".
+// When Type changes the format type, the current options are dropped and only
+// the options set in the patch are used.
+type PhysicalDatasetFormatPatch struct {
+	Type                    *string `json:"type,omitempty"`
+	FieldDelimiter          *string `json:"fieldDelimiter,omitempty"`
+	LineDelimiter           *string `json:"lineDelimiter,omitempty"`
+	Quote                   *string `json:"quote,omitempty"`
+	Comment                 *string `json:"comment,omitempty"`
+	Escape                  *string `json:"escape,omitempty"`
+	SkipFirstLine           *bool   `json:"skipFirstLine,omitempty"`
+	ExtractHeader           *bool   `json:"extractHeader,omitempty"`
+	TrimHeader              *bool   `json:"trimHeader,omitempty"`
+	AutoGenerateColumnNames *bool   `json:"autoGenerateColumnNames,omitempty"`
+	SheetName               *string `json:"sheetName,omitempty"`
+	HasMergedCells          *bool   `json:"hasMergedCells,omitempty"`
+	AutoCorrectCorruptDates *bool   `json:"autoCorrectCorruptDates,omitempty"`
+	IgnoreOtherFileFormats  *bool   `json:"ignoreOtherFileFormats,omitempty"`
+}
+
+func patchFormat(original *PhysicalDatasetFormat, patch *PhysicalDatasetFormatPatch) (*PhysicalDatasetFormat, error) {
+	if original != nil && patch.Type != nil && *patch.Type != original.Type {
+		original = nil
+	}
+	format := new(PhysicalDatasetFormat)
+	if err := mergeJsonInto(format, original, patch); err != nil {
+		return nil, err
+	}
+	if err := format.validate(patch.Type != nil); err != nil {
+		return nil, err
+	}
+	return format, nil
+}
+
+// DatasetAccelerationRefreshPolicyPatch holds the refresh policy settings to
+// change. Nil fields keep their current value.
+type DatasetAccelerationRefreshPolicyPatch struct {
+	RefreshPeriodMs *int64         `json:"refreshPeriodMs,omitempty"`
+	GracePeriodMs   *int64         `json:"gracePeriodMs,omitempty"`
+	Method          *RefreshMethod `json:"method,omitempty"`
+	RefreshField    *string        `json:"refreshField,omitempty"`
+	NeverExpire     *bool          `json:"neverExpire,omitempty"`
+	NeverRefresh    *bool          `json:"neverRefresh,omitempty"`
+}
+
+func (c *Client) PatchPhysicalDataset(id string, spec *PatchPhysicalDatasetSpec) (*PhysicalDataset, error) {
+	original, err := c.GetPhysicalDataset(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTag(id, spec.Tag, original.Tag); err != nil {
+		return nil, err
+	}
+	dataset := *original
+	dataset.Tag = writeTag(spec.Tag, original.Tag)
+	if spec.Format != nil {
+		format, err := patchFormat(original.Format, spec.Format)
+		if err != nil {
+			return nil, err
+		}
+		dataset.Format = format
+	}
+	if spec.AccelerationRefreshPolicy != nil {
+		policy := new(DatasetAccelerationRefreshPolicy)
+		if err := mergeJsonInto(policy, original.AccelerationRefreshPolicy, spec.AccelerationRefreshPolicy); err != nil {
+			return nil, err
+		}
+		if err := policy.Validate(); err != nil {
+			return nil, err
+		}
+		dataset.AccelerationRefreshPolicy = policy
+	}
 	result := new(PhysicalDataset)
 	err = c.updateCatalogItem(id, dataset, result)
 	if err != nil {
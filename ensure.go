@@ -0,0 +1,401 @@
+package dapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EnsureVirtualDataset creates the virtual dataset at spec.Path or updates it
+// so that its SQL matches the spec. The returned bool reports whether
+// anything was changed.
+func (c *Client) EnsureVirtualDataset(spec *NewVirtualDatasetSpec) (*VirtualDataset, bool, error) {
+	entity, err := c.GetCatalogEntityByPath(spec.Path)
+	if IsNotFound(err) {
+		result, err := c.NewVirtualDataset(spec)
+		return result, err == nil, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	existing, err := c.GetVirtualDataset(entity.Id)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing.Sql == spec.Sql && stringSlicesEqual(existing.SqlContext, spec.SqlContext) {
+		return existing, false, nil
+	}
+	result, err := c.UpdateVirtualDataset(existing.Id, &UpdateVirtualDatasetSpec{
+		Tag:        existing.Tag,
+		Sql:        spec.Sql,
+		SqlContext: spec.SqlContext,
+	})
+	return result, err == nil, err
+}
+
+// EnsureFolder creates the folder at spec.Path along with any missing parent
+// folders. The first path element must name an existing space or source.
+func (c *Client) EnsureFolder(spec *NewFolderSpec) (*Folder, bool, error) {
+	return c.newFolderRecursive(spec.Path)
+}
+
+func (c *Client) EnsureSpace(spec *NewSpaceSpec) (*Space, bool, error) {
+	entity, err := c.GetCatalogEntityByPath([]string{spec.Name})
+	if IsNotFound(err) {
+		result, err := c.NewSpace(spec)
+		return result, err == nil, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	result, err := c.GetSpace(entity.Id)
+	return result, false, err
+}
+
+// EnsureSource creates the source named spec.Name or updates it when the
+// source differs from the spec. See specMatches for how values are compared.
+func (c *Client) EnsureSource(spec *NewSourceSpec) (*Source, bool, error) {
+	entity, err := c.GetCatalogEntityByPath([]string{spec.Name})
+	if IsNotFound(err) {
+		result, err := c.NewSource(spec)
+		return result, err == nil, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	existing, err := c.GetSource(entity.Id)
+	if err != nil {
+		return nil, false, err
+	}
+	if spec.Type != "" && existing.Type != spec.Type {
+		return nil, false, errors.New("Source type cannot be changed")
+	}
+	matches, err := specMatches(existing, spec.source(), sourceSpecFields...)
+	if err != nil || matches {
+		return existing, false, err
+	}
+	result, err := c.UpdateSource(existing.Id, &UpdateSourceSpec{
+		Tag:                         existing.Tag,
+		Description:                 spec.Description,
+		Config:                      spec.Config,
+		MetadataPolicy:              spec.MetadataPolicy,
+		AccelerationRefreshPeriodMs: spec.AccelerationRefreshPeriodMs,
+		AccelerationGracePeriodMs:   spec.AccelerationGracePeriodMs,
+		AccelerationNeverExpire:     spec.AccelerationNeverExpire,
+		AccelerationNeverRefresh:    spec.AccelerationNeverRefresh,
+	})
+	return result, err == nil, err
+}
+
+// EnsureRawReflection creates or updates the raw reflection named spec.Name
+// on the given dataset.
+func (c *Client) EnsureRawReflection(datasetId string, spec *RawReflectionSpec) (*RawReflection, bool, error) {
+	id, err := c.findDatasetReflection(datasetId, spec.Name, "RAW")
+	if err != nil {
+		return nil, false, err
+	}
+	if id == "" {
+		result, err := c.NewRawReflection(datasetId, spec)
+		return result, err == nil, err
+	}
+	existing, err := c.GetRawReflection(id)
+	if err != nil {
+		return nil, false, err
+	}
+	matches, err := specMatches(existing, spec.reflection(datasetId), rawReflectionSpecFields...)
+	if err != nil || matches {
+		return existing, false, err
+	}
+	result, err := c.UpdateRawReflection(id, spec)
+	return result, err == nil, err
+}
+
+// EnsureAggregationReflection creates or updates the aggregation reflection
+// named spec.Name on the given dataset.
+func (c *Client) EnsureAggregationReflection(datasetId string, spec *AggregationReflectionSpec) (*AggregationReflection, bool, error) {
+	id, err := c.findDatasetReflection(datasetId, spec.Name, "AGGREGATION")
+	if err != nil {
+		return nil, false, err
+	}
+	if id == "" {
+		result, err := c.NewAggregationReflection(datasetId, spec)
+		return result, err == nil, err
+	}
+	existing, err := c.GetAggregationReflection(id)
+	if err != nil {
+		return nil, false, err
+	}
+	want := spec.reflection(datasetId)
+	defaultMeasureTypes(want.MeasureFields, existing.MeasureFields)
+	matches, err := specMatches(existing, want, aggregationReflectionSpecFields...)
+	if err != nil || matches {
+		return existing, false, err
+	}
+	result, err := c.UpdateAggregationReflection(id, spec)
+	return result, err == nil, err
+}
+
+func (c *Client) findDatasetReflection(datasetId string, name string, reflectionType string) (string, error) {
+	reflections, err := c.getDatasetReflections(datasetId)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range reflections {
+		if r.Name != name {
+			continue
+		}
+		if r.Type != reflectionType {
+			return "", errors.New("Reflection " + name + " is not " + reflectionType)
+		}
+		return r.Id, nil
+	}
+	return "", nil
+}
+
+var sourceSpecFields = []string{
+	"description", "config", "metadataPolicy", "accelerationRefreshPeriodMs",
+	"accelerationGracePeriodMs", "accelerationNeverExpire", "accelerationNeverRefresh",
+}
+
+var rawReflectionSpecFields = []string{
+	"name", "enabled", "displayFields", "distributionFields", "partitionFields",
+	"sortFields", "partitionDistributionStrategy",
+}
+
+var aggregationReflectionSpecFields = []string{
+	"name", "enabled", "dimensionFields", "measureFields", "distributionFields",
+	"partitionFields", "sortFields", "partitionDistributionStrategy",
+}
+
+// defaultMeasureTypes gives measures without measure types the types Dremio
+// chose for them, since an empty list asks for the server defaults.
+func defaultMeasureTypes(want []ReflectionMeasureField, have []ReflectionMeasureField) {
+	for i := range want {
+		if len(want[i].MeasureTypeList) > 0 {
+			continue
+		}
+		for _, h := range have {
+			if h.Name == want[i].Name {
+				want[i].MeasureTypeList = h.MeasureTypeList
+			}
+		}
+	}
+}
+
+// secretValue stands in for a Secret when comparing a spec with the server,
+// which never returns stored credentials.
+type secretValue struct{}
+
+// specMatches reports whether the JSON fields of have named in fields hold
+// the values of the same fields in want. Empty slices, maps and false
+// booleans in want are compared, so removing list entries or turning an
+// option off is detected. Zero numbers, empty strings and nil pointers in
+// omitempty struct fields are left for the server to default and are not
+// compared, and neither are secrets.
+func specMatches(have interface{}, want interface{}, fields ...string) (bool, error) {
+	haveValue, err := toJsonValue(have)
+	if err != nil {
+		return false, err
+	}
+	wantValue, err := specValue(reflect.ValueOf(want))
+	if err != nil {
+		return false, err
+	}
+	haveMap, _ := haveValue.(map[string]interface{})
+	wantMap, _ := wantValue.(map[string]interface{})
+	for _, f := range fields {
+		w, ok := wantMap[f]
+		if ok && !jsonValueMatches(haveMap[f], w) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func toJsonValue(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	err = json.Unmarshal(raw, &result)
+	return result, err
+}
+
+// specValue converts v to the value it would have after a JSON round trip,
+// keeping the struct fields described on specMatches even when they are
+// omitted from the encoding.
+func specValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if v.Type() == secretType {
+		return secretValue{}, nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+	if _, ok := v.Interface().(json.Marshaler); ok {
+		return toJsonValue(v.Interface())
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return specValue(v.Elem())
+	case reflect.Struct:
+		result := make(map[string]interface{})
+		if err := specStructFields(v, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case reflect.Map:
+		result := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value, err := specValue(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			result[fmt.Sprint(iter.Key().Interface())] = value
+		}
+		return result, nil
+	case reflect.Slice, reflect.Array:
+		result := make([]interface{}, v.Len())
+		for i := range result {
+			value, err := specValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = value
+		}
+		return result, nil
+	default:
+		return toJsonValue(v.Interface())
+	}
+}
+
+func specStructFields(v reflect.Value, result map[string]interface{}) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if name == "" && field.Anonymous && fv.Kind() == reflect.Struct {
+			if err := specStructFields(fv, result); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if len(tag) > 1 && tag[1] == "omitempty" && serverDefaulted(fv) {
+			continue
+		}
+		value, err := specValue(fv)
+		if err != nil {
+			return err
+		}
+		result[name] = value
+	}
+	return nil
+}
+
+func serverDefaulted(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return v.Len() == 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+func jsonValueMatches(have interface{}, want interface{}) bool {
+	if s, ok := have.(string); ok && s == ExistingSecretValue {
+		return true
+	}
+	switch w := want.(type) {
+	case secretValue:
+		return true
+	case nil:
+		return isZeroJsonValue(have)
+	case map[string]interface{}:
+		h, ok := have.(map[string]interface{})
+		if !ok && have != nil {
+			return false
+		}
+		for k, v := range w {
+			if !jsonValueMatches(h[k], v) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		h, ok := have.([]interface{})
+		if (!ok && have != nil) || len(h) != len(w) {
+			return false
+		}
+		for i := range w {
+			if !jsonValueMatches(h[i], w[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		if have == nil {
+			return isZeroJsonValue(want)
+		}
+		return reflect.DeepEqual(have, want)
+	}
+}
+
+func isZeroJsonValue(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !value
+	case float64:
+		return value == 0
+	case string:
+		return value == ""
+	case []interface{}:
+		return len(value) == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+func stringSlicesEqual(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
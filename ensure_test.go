@@ -0,0 +1,204 @@
+package dapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSpecMatches(t *testing.T) {
+	raw := func(spec *RawReflectionSpec) RawReflection {
+		return spec.reflection("ds")
+	}
+	serverReflection := func(body string) *RawReflection {
+		r := new(RawReflection)
+		if err := json.Unmarshal([]byte(body), r); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	existing := serverReflection(`{
+		"id": "r1", "tag": "t1", "name": "raw", "enabled": true, "type": "RAW", "datasetId": "ds",
+		"displayFields": [{"name": "a"}, {"name": "b"}],
+		"partitionFields": [{"name": "a"}],
+		"partitionDistributionStrategy": "CONSOLIDATED",
+		"status": {"config": "OK", "refresh": "SCHEDULED"}
+	}`)
+
+	tests := []struct {
+		name   string
+		have   interface{}
+		want   interface{}
+		fields []string
+		match  bool
+	}{
+		{
+			name: "same reflection",
+			have: existing,
+			want: raw(&RawReflectionSpec{
+				Name:            "raw",
+				Enabled:         true,
+				DisplayFields:   []ReflectionField{{Name: "a"}, {Name: "b"}},
+				PartitionFields: []ReflectionField{{Name: "a"}},
+			}),
+			fields: rawReflectionSpecFields,
+			match:  true,
+		},
+		{
+			name: "partition fields removed",
+			have: existing,
+			want: raw(&RawReflectionSpec{
+				Name:          "raw",
+				Enabled:       true,
+				DisplayFields: []ReflectionField{{Name: "a"}, {Name: "b"}},
+			}),
+			fields: rawReflectionSpecFields,
+			match:  false,
+		},
+		{
+			name: "disabled",
+			have: existing,
+			want: raw(&RawReflectionSpec{
+				Name:            "raw",
+				DisplayFields:   []ReflectionField{{Name: "a"}, {Name: "b"}},
+				PartitionFields: []ReflectionField{{Name: "a"}},
+			}),
+			fields: rawReflectionSpecFields,
+			match:  false,
+		},
+		{
+			name: "display field added",
+			have: existing,
+			want: raw(&RawReflectionSpec{
+				Name:            "raw",
+				Enabled:         true,
+				DisplayFields:   []ReflectionField{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+				PartitionFields: []ReflectionField{{Name: "a"}},
+			}),
+			fields: rawReflectionSpecFields,
+			match:  false,
+		},
+		{
+			name: "source bool turned off",
+			have: &Source{
+				Config:                  map[string]interface{}{"hostname": "db", "useSsl": true},
+				AccelerationNeverExpire: true,
+			},
+			want: Source{
+				Config: map[string]interface{}{"hostname": "db", "useSsl": true},
+			},
+			fields: sourceSpecFields,
+			match:  false,
+		},
+		{
+			name: "source config bool turned off",
+			have: &Source{
+				Config: map[string]interface{}{"hostname": "db", "useSsl": true},
+			},
+			want: Source{
+				Config: &PostgresConfig{Hostname: "db"},
+			},
+			fields: []string{"config"},
+			match:  false,
+		},
+		{
+			name: "stored secret is not compared",
+			have: &Source{
+				Config: map[string]interface{}{"hostname": "db", "password": ExistingSecretValue},
+			},
+			want: Source{
+				Config: map[string]interface{}{"hostname": "db", "password": "hunter2"},
+			},
+			fields: sourceSpecFields,
+			match:  true,
+		},
+		{
+			name: "typed secret is not compared",
+			have: &Source{
+				Config: map[string]interface{}{"hostname": "db", "password": ExistingSecretValue},
+			},
+			want: Source{
+				Config: &PostgresConfig{Hostname: "db", Password: SecretRef("db-password")},
+			},
+			fields: []string{"config"},
+			match:  true,
+		},
+		{
+			name: "server defaults are not compared",
+			have: &Source{
+				Config:                      map[string]interface{}{"hostname": "db", "port": "5432"},
+				AccelerationRefreshPeriodMs: 3600000,
+				MetadataPolicy:              &SourceMetadataPolicy{NamesRefreshMs: 3600000},
+			},
+			want: Source{
+				Config: &PostgresConfig{Hostname: "db"},
+			},
+			fields: sourceSpecFields,
+			match:  true,
+		},
+		{
+			name: "s3 options left at server defaults",
+			have: &Source{
+				Config: map[string]interface{}{
+					"credentialType": "ACCESS_KEY", "accessKey": "AKIA", "accessSecret": ExistingSecretValue,
+					"secure": true, "enableAsync": true, "enableFileStatusCheck": true,
+					"isCachingEnabled": true, "maxCacheSpacePct": 100.0, "rootPath": "/",
+				},
+			},
+			want: Source{
+				Config: &S3Config{CredentialType: "ACCESS_KEY", AccessKey: "AKIA", AccessSecret: NewSecret("s")},
+			},
+			fields: sourceSpecFields,
+			match:  true,
+		},
+		{
+			name: "s3 option turned off",
+			have: &Source{
+				Config: map[string]interface{}{"credentialType": "NONE", "enableAsync": true, "isCachingEnabled": true},
+			},
+			want: Source{
+				Config: &S3Config{CredentialType: "NONE", EnableAsync: Bool(false)},
+			},
+			fields: sourceSpecFields,
+			match:  false,
+		},
+		{
+			name: "changed policy",
+			have: &Source{
+				MetadataPolicy: &SourceMetadataPolicy{NamesRefreshMs: 3600000},
+			},
+			want: Source{
+				MetadataPolicy: &SourceMetadataPolicy{NamesRefreshMs: 60000},
+			},
+			fields: sourceSpecFields,
+			match:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := specMatches(tt.have, tt.want, tt.fields...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if match != tt.match {
+				t.Errorf("specMatches() = %v, want %v", match, tt.match)
+			}
+		})
+	}
+}
+
+func TestDefaultMeasureTypes(t *testing.T) {
+	have := []ReflectionMeasureField{
+		{ReflectionField: ReflectionField{Name: "amount"}, MeasureTypeList: []string{MeasureSum, MeasureCount}},
+	}
+	want := []ReflectionMeasureField{
+		{ReflectionField: ReflectionField{Name: "amount"}},
+		{ReflectionField: ReflectionField{Name: "other"}},
+	}
+	defaultMeasureTypes(want, have)
+	if !stringSlicesEqual(want[0].MeasureTypeList, have[0].MeasureTypeList) {
+		t.Errorf("measure types = %v, want %v", want[0].MeasureTypeList, have[0].MeasureTypeList)
+	}
+	if len(want[1].MeasureTypeList) != 0 {
+		t.Errorf("measure types of unknown field = %v, want none", want[1].MeasureTypeList)
+	}
+}
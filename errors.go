@@ -0,0 +1,83 @@
+package dapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type ApiError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *ApiError) Error() string {
+	return fmt.Sprintf("status: %d, body: %v", e.StatusCode, e.Body)
+}
+
+// Message returns the errorMessage field of the JSON error body Dremio sends,
+// or "" if the body has no such field.
+func (e *ApiError) Message() string {
+	var body struct {
+		ErrorMessage string `json:"errorMessage"`
+	}
+	if err := json.Unmarshal([]byte(e.Body), &body); err != nil {
+		return ""
+	}
+	return body.ErrorMessage
+}
+
+func IsNotFound(err error) bool {
+	var apiErr *ApiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+func isAlreadyExists(err error) bool {
+	var apiErr *ApiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+// ConflictError is returned when an update is rejected because the entity was
+// modified after the caller read it.
+type ConflictError struct {
+	Id          string
+	ExpectedTag string
+	ActualTag   string
+	Err         error
+}
+
+func (e *ConflictError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("conflicting update of %s: %v", e.Id, e.Err)
+	}
+	return fmt.Sprintf("conflicting update of %s: expected tag %s, found %s", e.Id, e.ExpectedTag, e.ActualTag)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+func IsConflict(err error) bool {
+	var conflictErr *ConflictError
+	return errors.As(err, &conflictErr)
+}
+
+// checkTag fails fast when the caller expects a tag other than the one just
+// read. The server still checks the tag sent with the update, see writeTag.
+func checkTag(id string, expected string, actual string) error {
+	if expected != "" && expected != actual {
+		return &ConflictError{Id: id, ExpectedTag: expected, ActualTag: actual}
+	}
+	return nil
+}
+
+// writeTag returns the tag to send with an update: the tag the caller
+// expects when one is given, so that the server rejects the write if the
+// entity changed after it was read, and otherwise the tag just read.
+func writeTag(expected string, actual string) string {
+	if expected != "" {
+		return expected
+	}
+	return actual
+}
@@ -41,3 +41,65 @@ func (c *Client) NewFolder(spec *NewFolderSpec) (*Folder, error) {
 	result.EnrichFields()
 	return result, err
 }
+
+// NewFolderRecursive creates the folder at spec.Path together with any missing
+// parent folders and returns the leaf folder. Folders created concurrently by
+// another client are treated as existing.
+func (c *Client) NewFolderRecursive(spec *NewFolderSpec) (*Folder, error) {
+	folder, _, err := c.newFolderRecursive(spec.Path)
+	return folder, err
+}
+
+func (c *Client) newFolderRecursive(path []string) (*Folder, bool, error) {
+	if len(path) < 2 {
+		return nil, false, errors.New("Folder path must include a parent space or source")
+	}
+	root, err := c.GetCatalogEntityByPath(path[:1])
+	if err != nil {
+		return nil, false, err
+	}
+	if root.EntityType != "space" && root.EntityType != "source" && root.EntityType != "home" {
+		return nil, false, errors.New("Catalog entity is not a space or source")
+	}
+	created := false
+	var result *Folder
+	for i := 2; i <= len(path); i++ {
+		folder, isNew, err := c.getOrCreateFolder(path[:i])
+		if err != nil {
+			return nil, created, err
+		}
+		created = created || isNew
+		result = folder
+	}
+	return result, created, nil
+}
+
+func (c *Client) getOrCreateFolder(path []string) (*Folder, bool, error) {
+	entity, err := c.GetCatalogEntityByPath(path)
+	if err == nil {
+		if entity.EntityType != "folder" {
+			return nil, false, errors.New("Catalog entity is not a folder")
+		}
+		folder, err := c.GetFolder(entity.Id)
+		return folder, false, err
+	}
+	if !IsNotFound(err) {
+		return nil, false, err
+	}
+	folder, err := c.NewFolder(&NewFolderSpec{Path: path})
+	if err == nil {
+		return folder, true, nil
+	}
+	if !isAlreadyExists(err) {
+		return nil, false, err
+	}
+	entity, err = c.GetCatalogEntityByPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+	if entity.EntityType != "folder" {
+		return nil, false, errors.New("Catalog entity is not a folder")
+	}
+	folder, err = c.GetFolder(entity.Id)
+	return folder, false, err
+}
@@ -0,0 +1,188 @@
+package dapi
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	FormatTypeText    = "Text"
+	FormatTypeJson    = "JSON"
+	FormatTypeParquet = "Parquet"
+	FormatTypeIceberg = "Iceberg"
+	FormatTypeDelta   = "Delta"
+	FormatTypeAvro    = "Avro"
+	FormatTypeExcel   = "Excel"
+	FormatTypeXls     = "XLS"
+)
+
+// DatasetFormat is implemented by the typed formats below, each of which
+// converts to the generic PhysicalDatasetFormat sent to Dremio.
+type DatasetFormat interface {
+	PhysicalDatasetFormat() *PhysicalDatasetFormat
+}
+
+type TextFormat struct {
+	FieldDelimiter          string
+	LineDelimiter           string
+	Quote                   string
+	Comment                 string
+	Escape                  string
+	SkipFirstLine           bool
+	ExtractHeader           bool
+	TrimHeader              bool
+	AutoGenerateColumnNames bool
+}
+
+func (f *TextFormat) PhysicalDatasetFormat() *PhysicalDatasetFormat {
+	return &PhysicalDatasetFormat{
+		Type:                    FormatTypeText,
+		FieldDelimiter:          f.FieldDelimiter,
+		LineDelimiter:           f.LineDelimiter,
+		Quote:                   f.Quote,
+		Comment:                 f.Comment,
+		Escape:                  f.Escape,
+		SkipFirstLine:           f.SkipFirstLine,
+		ExtractHeader:           f.ExtractHeader,
+		TrimHeader:              f.TrimHeader,
+		AutoGenerateColumnNames: f.AutoGenerateColumnNames,
+	}
+}
+
+type ParquetFormat struct {
+	AutoCorrectCorruptDates bool
+	IgnoreOtherFileFormats  bool
+}
+
+func (f *ParquetFormat) PhysicalDatasetFormat() *PhysicalDatasetFormat {
+	return &PhysicalDatasetFormat{
+		Type:                    FormatTypeParquet,
+		AutoCorrectCorruptDates: f.AutoCorrectCorruptDates,
+		IgnoreOtherFileFormats:  f.IgnoreOtherFileFormats,
+	}
+}
+
+type JsonFormat struct{}
+
+func (f *JsonFormat) PhysicalDatasetFormat() *PhysicalDatasetFormat {
+	return &PhysicalDatasetFormat{Type: FormatTypeJson}
+}
+
+type IcebergFormat struct{}
+
+func (f *IcebergFormat) PhysicalDatasetFormat() *PhysicalDatasetFormat {
+	return &PhysicalDatasetFormat{Type: FormatTypeIceberg}
+}
+
+type DeltaFormat struct{}
+
+func (f *DeltaFormat) PhysicalDatasetFormat() *PhysicalDatasetFormat {
+	return &PhysicalDatasetFormat{Type: FormatTypeDelta}
+}
+
+type AvroFormat struct{}
+
+func (f *AvroFormat) PhysicalDatasetFormat() *PhysicalDatasetFormat {
+	return &PhysicalDatasetFormat{Type: FormatTypeAvro}
+}
+
+// ExcelFormat reads XLSX workbooks.
+type ExcelFormat struct {
+	SheetName      string
+	ExtractHeader  bool
+	HasMergedCells bool
+}
+
+func (f *ExcelFormat) PhysicalDatasetFormat() *PhysicalDatasetFormat {
+	return &PhysicalDatasetFormat{
+		Type:           FormatTypeExcel,
+		SheetName:      f.SheetName,
+		ExtractHeader:  f.ExtractHeader,
+		HasMergedCells: f.HasMergedCells,
+	}
+}
+
+// XlsFormat reads legacy XLS workbooks.
+type XlsFormat struct {
+	SheetName      string
+	ExtractHeader  bool
+	HasMergedCells bool
+}
+
+func (f *XlsFormat) PhysicalDatasetFormat() *PhysicalDatasetFormat {
+	return &PhysicalDatasetFormat{
+		Type:           FormatTypeXls,
+		SheetName:      f.SheetName,
+		ExtractHeader:  f.ExtractHeader,
+		HasMergedCells: f.HasMergedCells,
+	}
+}
+
+// Validate checks that the format type is known and that only options
+// supported by that type are set.
+func (f *PhysicalDatasetFormat) Validate() error {
+	return f.validate(true)
+}
+
+// validate checks the options of the format. The type itself is only checked
+// when checkType is set, so that a format read from Dremio with a type not
+// listed here can still be patched.
+func (f *PhysicalDatasetFormat) validate(checkType bool) error {
+	if f == nil {
+		return nil
+	}
+	switch f.Type {
+	case FormatTypeText, FormatTypeJson, FormatTypeParquet, FormatTypeIceberg, FormatTypeDelta,
+		FormatTypeAvro, FormatTypeExcel, FormatTypeXls:
+	case "":
+		return fmt.Errorf("format type is required")
+	default:
+		if checkType {
+			return fmt.Errorf("unknown format type %s", f.Type)
+		}
+	}
+	text := f.FieldDelimiter != "" || f.LineDelimiter != "" || f.Quote != "" || f.Comment != "" ||
+		f.Escape != "" || f.SkipFirstLine || f.TrimHeader || f.AutoGenerateColumnNames
+	if text && f.Type != FormatTypeText {
+		return fmt.Errorf("%s format does not support text options", f.Type)
+	}
+	workbook := f.Type == FormatTypeExcel || f.Type == FormatTypeXls
+	if (f.SheetName != "" || f.HasMergedCells) && !workbook {
+		return fmt.Errorf("%s format does not support sheet options", f.Type)
+	}
+	if f.ExtractHeader && f.Type != FormatTypeText && !workbook {
+		return fmt.Errorf("%s format does not support extractHeader", f.Type)
+	}
+	if (f.AutoCorrectCorruptDates || f.IgnoreOtherFileFormats) && f.Type != FormatTypeParquet {
+		return fmt.Errorf("%s format does not support parquet options", f.Type)
+	}
+	return nil
+}
+
+// InferFormat picks a format for a file from its extension. Table formats
+// such as Iceberg and Delta Lake are folders and cannot be inferred this way.
+func InferFormat(fileName string) (*PhysicalDatasetFormat, error) {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv":
+		return (&TextFormat{FieldDelimiter: ",", LineDelimiter: "\n", Quote: `"`, Escape: `"`, ExtractHeader: true}).PhysicalDatasetFormat(), nil
+	case ".tsv", ".tab":
+		return (&TextFormat{FieldDelimiter: "\t", LineDelimiter: "\n", Quote: `"`, Escape: `"`, ExtractHeader: true}).PhysicalDatasetFormat(), nil
+	case ".psv":
+		return (&TextFormat{FieldDelimiter: "|", LineDelimiter: "\n", Quote: `"`, Escape: `"`, ExtractHeader: true}).PhysicalDatasetFormat(), nil
+	case ".txt":
+		return (&TextFormat{FieldDelimiter: ",", LineDelimiter: "\n", Quote: `"`, Escape: `"`}).PhysicalDatasetFormat(), nil
+	case ".json":
+		return (&JsonFormat{}).PhysicalDatasetFormat(), nil
+	case ".parquet":
+		return (&ParquetFormat{}).PhysicalDatasetFormat(), nil
+	case ".avro":
+		return (&AvroFormat{}).PhysicalDatasetFormat(), nil
+	case ".xlsx":
+		return (&ExcelFormat{ExtractHeader: true}).PhysicalDatasetFormat(), nil
+	case ".xls":
+		return (&XlsFormat{ExtractHeader: true}).PhysicalDatasetFormat(), nil
+	default:
+		return nil, fmt.Errorf("cannot infer format of %s", fileName)
+	}
+}
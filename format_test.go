@@ -0,0 +1,84 @@
+package dapi
+
+import "testing"
+
+func TestPhysicalDatasetFormatValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  *PhysicalDatasetFormat
+		wantErr bool
+	}{
+		{name: "nil", format: nil},
+		{name: "text defaults", format: &PhysicalDatasetFormat{Type: FormatTypeText, FieldDelimiter: ",", TrimHeader: true}},
+		{name: "text with header", format: &PhysicalDatasetFormat{Type: FormatTypeText, ExtractHeader: true, TrimHeader: true}},
+		{name: "parquet options", format: &PhysicalDatasetFormat{Type: FormatTypeParquet, AutoCorrectCorruptDates: true}},
+		{name: "excel sheet", format: &PhysicalDatasetFormat{Type: FormatTypeExcel, SheetName: "s", ExtractHeader: true}},
+		{name: "missing type", format: &PhysicalDatasetFormat{}, wantErr: true},
+		{name: "unknown type", format: &PhysicalDatasetFormat{Type: "Arrow"}, wantErr: true},
+		{name: "text option on parquet", format: &PhysicalDatasetFormat{Type: FormatTypeParquet, FieldDelimiter: ","}, wantErr: true},
+		{name: "parquet option on json", format: &PhysicalDatasetFormat{Type: FormatTypeJson, IgnoreOtherFileFormats: true}, wantErr: true},
+		{name: "sheet option on text", format: &PhysicalDatasetFormat{Type: FormatTypeText, SheetName: "s"}, wantErr: true},
+		{name: "header on avro", format: &PhysicalDatasetFormat{Type: FormatTypeAvro, ExtractHeader: true}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.format.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPhysicalDatasetFormatValidateUnknownServerType(t *testing.T) {
+	format := &PhysicalDatasetFormat{Type: "Arrow"}
+	if err := format.validate(false); err != nil {
+		t.Errorf("validate(false) = %v, want nil", err)
+	}
+	format.FieldDelimiter = ","
+	if err := format.validate(false); err == nil {
+		t.Error("validate(false) = nil, want error for text options")
+	}
+}
+
+func TestInferFormat(t *testing.T) {
+	tests := []struct {
+		fileName  string
+		wantType  string
+		delimiter string
+		header    bool
+		wantErr   bool
+	}{
+		{fileName: "orders.csv", wantType: FormatTypeText, delimiter: ",", header: true},
+		{fileName: "orders.TSV", wantType: FormatTypeText, delimiter: "\t", header: true},
+		{fileName: "orders.psv", wantType: FormatTypeText, delimiter: "|", header: true},
+		{fileName: "orders.txt", wantType: FormatTypeText, delimiter: ","},
+		{fileName: "dir/orders.json", wantType: FormatTypeJson},
+		{fileName: "orders.parquet", wantType: FormatTypeParquet},
+		{fileName: "orders.avro", wantType: FormatTypeAvro},
+		{fileName: "orders.xlsx", wantType: FormatTypeExcel, header: true},
+		{fileName: "orders.xls", wantType: FormatTypeXls, header: true},
+		{fileName: "orders", wantErr: true},
+		{fileName: "orders.orc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			format, err := InferFormat(tt.fileName)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("InferFormat() = %+v, want error", format)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format.Type != tt.wantType || format.FieldDelimiter != tt.delimiter || format.ExtractHeader != tt.header {
+				t.Errorf("InferFormat() = %+v, want type %s, delimiter %q, header %v", format, tt.wantType, tt.delimiter, tt.header)
+			}
+			if err := format.Validate(); err != nil {
+				t.Errorf("inferred format is invalid: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package dapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+const (
+	GranteeTypeUser = "USER"
+	GranteeTypeRole = "ROLE"
+)
+
+const (
+	PrivilegeSelect          = "SELECT"
+	PrivilegeAlter           = "ALTER"
+	PrivilegeManageGrants    = "MANAGE_GRANTS"
+	PrivilegeModify          = "MODIFY"
+	PrivilegeCreateTable     = "CREATE_TABLE"
+	PrivilegeDropTable       = "DROP"
+	PrivilegeInsert          = "INSERT"
+	PrivilegeUpdate          = "UPDATE"
+	PrivilegeDelete          = "DELETE"
+	PrivilegeTruncate        = "TRUNCATE"
+	PrivilegeViewReflection  = "VIEW_REFLECTION"
+	PrivilegeAlterReflection = "ALTER_REFLECTION"
+	PrivilegeOwnership       = "OWNERSHIP"
+)
+
+const publicRole = "PUBLIC"
+
+// AccessControlList is the legacy sharing model of catalog entities.
+type AccessControlList struct {
+	Users []AccessControl `json:"users,omitempty"`
+	Roles []AccessControl `json:"roles,omitempty"`
+}
+
+type AccessControl struct {
+	Id          string   `json:"id"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+type CatalogGrant struct {
+	GranteeType string   `json:"granteeType"`
+	Id          string   `json:"id"`
+	Name        string   `json:"name,omitempty"`
+	Privileges  []string `json:"privileges"`
+}
+
+type CatalogGrants struct {
+	Id                  string         `json:"id,omitempty"`
+	AvailablePrivileges []string       `json:"availablePrivileges,omitempty"`
+	Grants              []CatalogGrant `json:"grants"`
+}
+
+func (c *Client) GetGrants(id string) (*CatalogGrants, error) {
+	response := new(CatalogGrants)
+	path := fmt.Sprintf("/api/v3/catalog/%s/grants", url.QueryEscape(id))
+	err := c.request("GET", path, nil, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// SetGrants replaces all grants on the catalog entity.
+func (c *Client) SetGrants(id string, grants []CatalogGrant) error {
+	if grants == nil {
+		grants = []CatalogGrant{}
+	}
+	body, err := json.Marshal(CatalogGrants{Grants: grants})
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/api/v3/catalog/%s/grants", url.QueryEscape(id))
+	return c.request("PUT", path, bytes.NewBuffer(body), nil)
+}
+
+// Grant gives a user or role the privileges on the catalog entity at path,
+// keeping any privileges already granted.
+func (c *Client) Grant(path []string, granteeType string, granteeId string, privileges ...string) error {
+	return c.modifyGrants(path, granteeType, granteeId, func(current []string) []string {
+		return mergePrivileges(current, privileges)
+	})
+}
+
+// Revoke removes privileges from a user or role on the catalog entity at
+// path. Without privileges every privilege of the grantee is revoked.
+func (c *Client) Revoke(path []string, granteeType string, granteeId string, privileges ...string) error {
+	return c.modifyGrants(path, granteeType, granteeId, func(current []string) []string {
+		if len(privileges) == 0 {
+			return nil
+		}
+		removed := make(map[string]bool, len(privileges))
+		for _, p := range privileges {
+			removed[p] = true
+		}
+		var result []string
+		for _, p := range current {
+			if !removed[p] {
+				result = append(result, p)
+			}
+		}
+		return result
+	})
+}
+
+func (c *Client) modifyGrants(path []string, granteeType string, granteeId string, modify func([]string) []string) error {
+	entity, err := c.GetCatalogEntityByPath(path)
+	if err != nil {
+		return err
+	}
+	grants, err := c.GetGrants(entity.Id)
+	if err != nil {
+		return err
+	}
+	var result []CatalogGrant
+	found := false
+	for _, g := range grants.Grants {
+		if g.GranteeType == granteeType && g.Id == granteeId {
+			found = true
+			g.Privileges = modify(g.Privileges)
+		}
+		if len(g.Privileges) > 0 {
+			result = append(result, g)
+		}
+	}
+	if !found {
+		privileges := modify(nil)
+		if len(privileges) > 0 {
+			result = append(result, CatalogGrant{GranteeType: granteeType, Id: granteeId, Privileges: privileges})
+		}
+	}
+	return c.SetGrants(entity.Id, result)
+}
+
+// EffectivePrivileges returns the privileges a user holds on the catalog
+// entity at path, combining grants made to the user, to the roles the user
+// belongs to and to PUBLIC on the entity and each of its parents.
+func (c *Client) EffectivePrivileges(path []string, userId string) ([]string, error) {
+	user, err := c.GetUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := c.expandRoles(user.Roles)
+	if err != nil {
+		return nil, err
+	}
+	var privileges []string
+	for i := 1; i <= len(path); i++ {
+		entity, err := c.GetCatalogEntityByPath(path[:i])
+		if err != nil {
+			return nil, err
+		}
+		grants, err := c.GetGrants(entity.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range grants.Grants {
+			switch {
+			case g.GranteeType == GranteeTypeUser && g.Id == user.Id,
+				g.GranteeType == GranteeTypeRole && (roles[g.Id] || g.Name == publicRole):
+				privileges = mergePrivileges(privileges, g.Privileges)
+			}
+		}
+	}
+	sort.Strings(privileges)
+	return privileges, nil
+}
+
+// expandRoles returns the ids of the given roles and of every role they are
+// members of, directly or indirectly.
+func (c *Client) expandRoles(direct []RoleReference) (map[string]bool, error) {
+	result := make(map[string]bool)
+	pending := append([]RoleReference{}, direct...)
+	for len(pending) > 0 {
+		ref := pending[0]
+		pending = pending[1:]
+		if result[ref.Id] {
+			continue
+		}
+		result[ref.Id] = true
+		role, err := c.GetRole(ref.Id)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, role.Roles...)
+	}
+	return result, nil
+}
+
+func mergePrivileges(current []string, added []string) []string {
+	seen := make(map[string]bool, len(current)+len(added))
+	var result []string
+	for _, p := range append(append([]string{}, current...), added...) {
+		if !seen[p] {
+			seen[p] = true
+			result = append(result, p)
+		}
+	}
+	return result
+}
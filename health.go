@@ -0,0 +1,93 @@
+package dapi
+
+import (
+	"time"
+)
+
+type SourceStatus string
+
+const (
+	SourceStatusGood SourceStatus = "good"
+	SourceStatusWarn SourceStatus = "warn"
+	SourceStatusBad  SourceStatus = "bad"
+)
+
+type SourceState struct {
+	Status              SourceStatus         `json:"status,omitempty"`
+	SuggestedUserAction string               `json:"suggestedUserAction,omitempty"`
+	Messages            []SourceStateMessage `json:"messages,omitempty"`
+}
+
+type SourceStateMessage struct {
+	Level   string `json:"level,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type SourceHealth struct {
+	Id                  string       `json:"id"`
+	Name                string       `json:"name"`
+	Type                string       `json:"type,omitempty"`
+	Status              SourceStatus `json:"status,omitempty"`
+	SuggestedUserAction string       `json:"suggestedUserAction,omitempty"`
+	Messages            []string     `json:"messages,omitempty"`
+	Error               string       `json:"error,omitempty"`
+}
+
+func (h *SourceHealth) Healthy() bool {
+	return h.Error == "" && h.Status == SourceStatusGood
+}
+
+type SourceHealthReport struct {
+	CheckedAt time.Time      `json:"checkedAt"`
+	Sources   []SourceHealth `json:"sources"`
+}
+
+func (r *SourceHealthReport) Healthy() bool {
+	return len(r.Unhealthy()) == 0
+}
+
+func (r *SourceHealthReport) Unhealthy() []SourceHealth {
+	var result []SourceHealth
+	for _, s := range r.Sources {
+		if !s.Healthy() {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// CheckSources reads the state of every source in the catalog. A source that
+// cannot be read is reported with its error rather than failing the check.
+func (c *Client) CheckSources() (*SourceHealthReport, error) {
+	root, err := c.GetRootCatalogSummary()
+	if err != nil {
+		return nil, err
+	}
+	report := &SourceHealthReport{CheckedAt: time.Now()}
+	for _, entry := range root {
+		if entry.ContainerType != "SOURCE" {
+			continue
+		}
+		health := SourceHealth{Id: entry.Id}
+		if len(entry.Path) > 0 {
+			health.Name = entry.Path[len(entry.Path)-1]
+		}
+		source, err := c.GetSource(entry.Id)
+		if err != nil {
+			health.Error = err.Error()
+			report.Sources = append(report.Sources, health)
+			continue
+		}
+		health.Name = source.Name
+		health.Type = source.Type
+		if source.State != nil {
+			health.Status = source.State.Status
+			health.SuggestedUserAction = source.State.SuggestedUserAction
+			for _, m := range source.State.Messages {
+				health.Messages = append(health.Messages, m.Message)
+			}
+		}
+		report.Sources = append(report.Sources, health)
+	}
+	return report, nil
+}
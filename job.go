@@ -0,0 +1,119 @@
+package dapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+const (
+	JobStateNotSubmitted          = "NOT_SUBMITTED"
+	JobStateStarting              = "STARTING"
+	JobStateRunning               = "RUNNING"
+	JobStateCompleted             = "COMPLETED"
+	JobStateCanceled              = "CANCELED"
+	JobStateFailed                = "FAILED"
+	JobStateCancellationRequested = "CANCELLATION_REQUESTED"
+	JobStateEnqueued              = "ENQUEUED"
+	JobStatePlanning              = "PLANNING"
+	JobStatePending               = "PENDING"
+	JobStateMetadataRetrieval     = "METADATA_RETRIEVAL"
+	JobStateQueued                = "QUEUED"
+	JobStateEngineStart           = "ENGINE_START"
+	JobStateExecutionPlanning     = "EXECUTION_PLANNING"
+)
+
+type Job struct {
+	Id                 string     `json:"-"`
+	JobState           string     `json:"jobState,omitempty"`
+	RowCount           int        `json:"rowCount,omitempty"`
+	ErrorMessage       string     `json:"errorMessage,omitempty"`
+	CancellationReason string     `json:"cancellationReason,omitempty"`
+	QueryType          string     `json:"queryType,omitempty"`
+	QueueName          string     `json:"queueName,omitempty"`
+	StartedAt          *Timestamp `json:"startedAt,omitempty"`
+	EndedAt            *Timestamp `json:"endedAt,omitempty"`
+}
+
+func (j *Job) Done() bool {
+	return j.JobState == JobStateCompleted || j.JobState == JobStateCanceled || j.JobState == JobStateFailed
+}
+
+type JobFailedError struct {
+	Id      string
+	State   string
+	Message string
+}
+
+func (e *JobFailedError) Error() string {
+	return fmt.Sprintf("job %s %s: %s", e.Id, e.State, e.Message)
+}
+
+type sqlRequest struct {
+	Sql     string   `json:"sql"`
+	Context []string `json:"context,omitempty"`
+}
+
+type sqlResponse struct {
+	Id string `json:"id"`
+}
+
+// SubmitSql starts a job running sql and returns the job id.
+func (c *Client) SubmitSql(sql string, context []string) (string, error) {
+	body, err := json.Marshal(sqlRequest{Sql: sql, Context: context})
+	if err != nil {
+		return "", err
+	}
+	response := new(sqlResponse)
+	err = c.request("POST", "/api/v3/sql", bytes.NewBuffer(body), response)
+	if err != nil {
+		return "", err
+	}
+	return response.Id, nil
+}
+
+func (c *Client) GetJob(id string) (*Job, error) {
+	job := new(Job)
+	path := fmt.Sprintf("/api/v3/job/%s", url.QueryEscape(id))
+	err := c.request("GET", path, nil, job)
+	if err != nil {
+		return nil, err
+	}
+	job.Id = id
+	return job, nil
+}
+
+// WaitForJob polls the job until it has finished. A job that failed or was
+// canceled is reported as a *JobFailedError.
+func (c *Client) WaitForJob(ctx context.Context, id string, opts *WaitOptions) (*Job, error) {
+	var job *Job
+	err := poll(ctx, opts.pollInterval(), func() (bool, error) {
+		var err error
+		job, err = c.GetJob(id)
+		if err != nil {
+			return false, err
+		}
+		return job.Done(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch job.JobState {
+	case JobStateFailed:
+		return job, &JobFailedError{Id: id, State: job.JobState, Message: job.ErrorMessage}
+	case JobStateCanceled:
+		return job, &JobFailedError{Id: id, State: job.JobState, Message: job.CancellationReason}
+	}
+	return job, nil
+}
+
+// RunSql submits sql and waits for the job to finish.
+func (c *Client) RunSql(ctx context.Context, sql string, context []string, opts *WaitOptions) (*Job, error) {
+	id, err := c.SubmitSql(sql, context)
+	if err != nil {
+		return nil, err
+	}
+	return c.WaitForJob(ctx, id, opts)
+}
@@ -0,0 +1,112 @@
+package dapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type MetadataRefreshOptions struct {
+	// Partitions limits a partial refresh to the partitions with these
+	// column values.
+	Partitions map[string]string
+	// Files limits a partial refresh to these files.
+	Files             []string
+	AvoidPromotion    bool
+	ForceUpdate       bool
+	DeleteWhenMissing bool
+	Wait              *WaitOptions
+}
+
+func (o *MetadataRefreshOptions) sql(path []string) string {
+	var sb strings.Builder
+	sb.WriteString("ALTER TABLE ")
+	sb.WriteString(quotePath(path))
+	sb.WriteString(" REFRESH METADATA")
+	if o == nil {
+		return sb.String()
+	}
+	switch {
+	case len(o.Partitions) > 0:
+		keys := make([]string, 0, len(o.Partitions))
+		for k := range o.Partitions {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		partitions := make([]string, len(keys))
+		for i, k := range keys {
+			partitions[i] = fmt.Sprintf("%s = %s", quotePath([]string{k}), quoteLiteral(o.Partitions[k]))
+		}
+		sb.WriteString(" FOR PARTITIONS (" + strings.Join(partitions, ", ") + ")")
+	case len(o.Files) > 0:
+		files := make([]string, len(o.Files))
+		for i, f := range o.Files {
+			files[i] = quoteLiteral(f)
+		}
+		sb.WriteString(" FOR FILES (" + strings.Join(files, ", ") + ")")
+	}
+	if o.AvoidPromotion {
+		sb.WriteString(" AVOID PROMOTION")
+	} else {
+		sb.WriteString(" AUTO PROMOTION")
+	}
+	if o.ForceUpdate {
+		sb.WriteString(" FORCE UPDATE")
+	} else {
+		sb.WriteString(" LAZY UPDATE")
+	}
+	if o.DeleteWhenMissing {
+		sb.WriteString(" DELETE WHEN MISSING")
+	} else {
+		sb.WriteString(" MAINTAIN WHEN MISSING")
+	}
+	return sb.String()
+}
+
+func (o *MetadataRefreshOptions) wait() *WaitOptions {
+	if o == nil {
+		return nil
+	}
+	return o.Wait
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// RefreshDatasetMetadata refreshes the metadata of the dataset at path and
+// waits for the refresh job to complete.
+func (c *Client) RefreshDatasetMetadata(ctx context.Context, path []string, opts *MetadataRefreshOptions) (*Job, error) {
+	return c.RunSql(ctx, opts.sql(path), nil, opts.wait())
+}
+
+// RefreshSourceMetadata refreshes the metadata of every dataset in the named
+// source, one job at a time. Partial refresh options cannot be used here as
+// they are specific to a single dataset.
+func (c *Client) RefreshSourceMetadata(ctx context.Context, name string, opts *MetadataRefreshOptions) ([]*Job, error) {
+	if opts != nil && (len(opts.Partitions) > 0 || len(opts.Files) > 0) {
+		return nil, errors.New("Partial metadata refresh is not supported for a whole source")
+	}
+	source, err := c.GetCatalogEntityByPath([]string{name})
+	if err != nil {
+		return nil, err
+	}
+	if source.EntityType != "source" {
+		return nil, errors.New("Catalog entity is not a source")
+	}
+	var jobs []*Job
+	err = c.walkCatalog(source.Id, func(child CatalogChild) error {
+		if child.Type != "DATASET" || child.DatasetType == "VIRTUAL" {
+			return nil
+		}
+		job, err := c.RefreshDatasetMetadata(ctx, child.Path, opts)
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, job)
+		return nil
+	})
+	return jobs, err
+}
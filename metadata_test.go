@@ -0,0 +1,44 @@
+package dapi
+
+import "testing"
+
+func TestMetadataRefreshOptionsSql(t *testing.T) {
+	path := []string{"s3", "bucket", "orders"}
+	tests := []struct {
+		name string
+		opts *MetadataRefreshOptions
+		want string
+	}{
+		{
+			name: "nil options",
+			want: `ALTER TABLE "s3"."bucket"."orders" REFRESH METADATA`,
+		},
+		{
+			name: "defaults",
+			opts: &MetadataRefreshOptions{},
+			want: `ALTER TABLE "s3"."bucket"."orders" REFRESH METADATA AUTO PROMOTION LAZY UPDATE MAINTAIN WHEN MISSING`,
+		},
+		{
+			name: "all flags",
+			opts: &MetadataRefreshOptions{AvoidPromotion: true, ForceUpdate: true, DeleteWhenMissing: true},
+			want: `ALTER TABLE "s3"."bucket"."orders" REFRESH METADATA AVOID PROMOTION FORCE UPDATE DELETE WHEN MISSING`,
+		},
+		{
+			name: "partitions sorted and quoted",
+			opts: &MetadataRefreshOptions{Partitions: map[string]string{"year": "2021", "dir0": "it's"}},
+			want: `ALTER TABLE "s3"."bucket"."orders" REFRESH METADATA FOR PARTITIONS ("dir0" = 'it''s', "year" = '2021') AUTO PROMOTION LAZY UPDATE MAINTAIN WHEN MISSING`,
+		},
+		{
+			name: "files",
+			opts: &MetadataRefreshOptions{Files: []string{"a.parquet", "b'.parquet"}},
+			want: `ALTER TABLE "s3"."bucket"."orders" REFRESH METADATA FOR FILES ('a.parquet', 'b''.parquet') AUTO PROMOTION LAZY UPDATE MAINTAIN WHEN MISSING`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.sql(path); got != tt.want {
+				t.Errorf("sql() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package dapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Owner struct {
+	OwnerId   string `json:"ownerId,omitempty"`
+	OwnerType string `json:"ownerType,omitempty"`
+}
+
+func (c *Client) GetOwner(id string) (*Owner, error) {
+	entity, err := c.GetCatalogEntityById(id)
+	if err != nil {
+		return nil, err
+	}
+	if entity.Owner == nil {
+		return nil, errors.New("Catalog entity does not report an owner")
+	}
+	return entity.Owner, nil
+}
+
+// ReflectionOwnershipError is returned by TransferOwnership when transferred
+// datasets have reflections. Dremio has no API or SQL to read or change the
+// owner of a reflection, so these reflections were left as they are.
+type ReflectionOwnershipError struct {
+	ReflectionIds []string
+}
+
+func (e *ReflectionOwnershipError) Error() string {
+	return fmt.Sprintf("changing the owner of reflections is not supported, reflections not transferred: %s",
+		strings.Join(e.ReflectionIds, ", "))
+}
+
+// SetOwner transfers ownership of the catalog entity at path to a user or
+// role.
+func (c *Client) SetOwner(ctx context.Context, path []string, ownerType string, ownerId string) error {
+	entity, err := c.GetCatalogEntityByPath(path)
+	if err != nil {
+		return err
+	}
+	return c.setOwner(ctx, entity, ownerType, ownerId)
+}
+
+func (c *Client) setOwner(ctx context.Context, entity *CatalogEntity, ownerType string, ownerId string) error {
+	object, err := c.ownershipObject(entity)
+	if err != nil {
+		return err
+	}
+	var name string
+	switch ownerType {
+	case GranteeTypeUser:
+		user, err := c.GetUser(ownerId)
+		if err != nil {
+			return err
+		}
+		name = user.Name
+	case GranteeTypeRole:
+		role, err := c.GetRole(ownerId)
+		if err != nil {
+			return err
+		}
+		name = role.Name
+	default:
+		return fmt.Errorf("unknown owner type %s", ownerType)
+	}
+	sql := fmt.Sprintf("GRANT OWNERSHIP ON %s %s TO %s %s", object, quotePath(entity.Path), ownerType, quotePath([]string{name}))
+	_, err = c.RunSql(ctx, sql, nil, nil)
+	return err
+}
+
+func (c *Client) ownershipObject(entity *CatalogEntity) (string, error) {
+	switch entity.EntityType {
+	case "space":
+		return "SPACE", nil
+	case "folder":
+		return "FOLDER", nil
+	case "source":
+		return "SOURCE", nil
+	case "dataset":
+		dataset, err := c.GetDataset(entity.Id)
+		if err != nil {
+			return "", err
+		}
+		if dataset.Type == "VIRTUAL_DATASET" {
+			return "VIEW", nil
+		}
+		return "TABLE", nil
+	default:
+		return "", fmt.Errorf("ownership of %s entities cannot be changed", entity.EntityType)
+	}
+}
+
+// TransferOwnership makes toUserId the owner of every entity at or below
+// scope that is owned by fromUserId, returning the paths that were changed.
+// Dremio does not report or change the owner of reflections, so when
+// transferred datasets have reflections the entities are still transferred
+// and a *ReflectionOwnershipError listing those reflections is returned.
+func (c *Client) TransferOwnership(ctx context.Context, fromUserId string, toUserId string, scope []string) ([][]string, error) {
+	root, err := c.GetCatalogEntityByPath(scope)
+	if err != nil {
+		return nil, err
+	}
+	var transferred [][]string
+	datasetIds := make(map[string]bool)
+	transfer := func(entity *CatalogEntity) error {
+		if entity.Owner == nil || entity.Owner.OwnerType != GranteeTypeUser || entity.Owner.OwnerId != fromUserId {
+			return nil
+		}
+		if err := c.setOwner(ctx, entity, GranteeTypeUser, toUserId); err != nil {
+			return err
+		}
+		transferred = append(transferred, entity.Path)
+		if entity.EntityType == "dataset" {
+			datasetIds[entity.Id] = true
+		}
+		return nil
+	}
+	if err := transfer(root); err != nil {
+		return transferred, err
+	}
+	err = c.walkCatalog(root.Id, func(child CatalogChild) error {
+		if child.Type != "CONTAINER" && child.Type != "DATASET" {
+			return nil
+		}
+		entity, err := c.GetCatalogEntityById(child.Id)
+		if err != nil {
+			return err
+		}
+		return transfer(entity)
+	})
+	if err != nil || len(datasetIds) == 0 {
+		return transferred, err
+	}
+	reflections, err := c.ListReflections()
+	if err != nil {
+		return transferred, err
+	}
+	var reflectionIds []string
+	for _, r := range reflections {
+		if datasetIds[r.ReflectionBase().DatasetId] {
+			reflectionIds = append(reflectionIds, r.ReflectionBase().Id)
+		}
+	}
+	if len(reflectionIds) > 0 {
+		return transferred, &ReflectionOwnershipError{ReflectionIds: reflectionIds}
+	}
+	return transferred, nil
+}
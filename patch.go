@@ -0,0 +1,64 @@
+package dapi
+
+import (
+	"encoding/json"
+)
+
+func String(v string) *string {
+	return &v
+}
+
+func Int64(v int64) *int64 {
+	return &v
+}
+
+func Bool(v bool) *bool {
+	return &v
+}
+
+// mergeJson overlays the JSON object encoding of patch onto that of original
+// and returns the merged object. Nested objects are merged recursively.
+func mergeJson(original interface{}, patch interface{}) (map[string]interface{}, error) {
+	base, err := toJsonValue(original)
+	if err != nil {
+		return nil, err
+	}
+	overlay, err := toJsonValue(patch)
+	if err != nil {
+		return nil, err
+	}
+	baseMap, _ := base.(map[string]interface{})
+	overlayMap, _ := overlay.(map[string]interface{})
+	return mergeJsonMaps(baseMap, overlayMap), nil
+}
+
+func mergeJsonMaps(base map[string]interface{}, overlay map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(base)+len(overlay))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range overlay {
+		baseChild, baseIsMap := result[k].(map[string]interface{})
+		overlayChild, overlayIsMap := v.(map[string]interface{})
+		if baseIsMap && overlayIsMap {
+			result[k] = mergeJsonMaps(baseChild, overlayChild)
+		} else {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// mergeJsonInto merges patch onto original as mergeJson does and decodes the
+// result into target.
+func mergeJsonInto(target interface{}, original interface{}, patch interface{}) error {
+	merged, err := mergeJson(original, patch)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, target)
+}
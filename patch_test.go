@@ -0,0 +1,133 @@
+package dapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeJson(t *testing.T) {
+	tests := []struct {
+		name     string
+		original interface{}
+		patch    interface{}
+		want     map[string]interface{}
+	}{
+		{
+			name:     "adds and replaces keys",
+			original: map[string]interface{}{"a": 1, "b": "x"},
+			patch:    map[string]interface{}{"b": "y", "c": true},
+			want:     map[string]interface{}{"a": 1.0, "b": "y", "c": true},
+		},
+		{
+			name:     "merges nested objects",
+			original: map[string]interface{}{"n": map[string]interface{}{"a": 1, "b": 2}},
+			patch:    map[string]interface{}{"n": map[string]interface{}{"b": 3}},
+			want:     map[string]interface{}{"n": map[string]interface{}{"a": 1.0, "b": 3.0}},
+		},
+		{
+			name:     "replaces lists",
+			original: map[string]interface{}{"l": []string{"a", "b"}},
+			patch:    map[string]interface{}{"l": []string{"c"}},
+			want:     map[string]interface{}{"l": []interface{}{"c"}},
+		},
+		{
+			name:     "sets false from a map",
+			original: map[string]interface{}{"useSsl": true},
+			patch:    map[string]interface{}{"useSsl": false},
+			want:     map[string]interface{}{"useSsl": false},
+		},
+		{
+			name:     "nil original",
+			original: nil,
+			patch:    map[string]interface{}{"a": "b"},
+			want:     map[string]interface{}{"a": "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mergeJson(tt.original, tt.patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeJson() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeJsonIntoPatchTypes(t *testing.T) {
+	policy := new(DatasetAccelerationRefreshPolicy)
+	err := mergeJsonInto(policy,
+		&DatasetAccelerationRefreshPolicy{RefreshPeriodMs: 3600000, NeverRefresh: true},
+		&DatasetAccelerationRefreshPolicyPatch{NeverRefresh: Bool(false), GracePeriodMs: Int64(7200000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPolicy := DatasetAccelerationRefreshPolicy{RefreshPeriodMs: 3600000, GracePeriodMs: 7200000}
+	if *policy != wantPolicy {
+		t.Errorf("policy = %+v, want %+v", *policy, wantPolicy)
+	}
+
+	format := new(PhysicalDatasetFormat)
+	err = mergeJsonInto(format,
+		&PhysicalDatasetFormat{Type: FormatTypeText, FieldDelimiter: ",", ExtractHeader: true},
+		&PhysicalDatasetFormatPatch{ExtractHeader: Bool(false), Quote: String("")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFormat := PhysicalDatasetFormat{Type: FormatTypeText, FieldDelimiter: ","}
+	if *format != wantFormat {
+		t.Errorf("format = %+v, want %+v", *format, wantFormat)
+	}
+
+	metadata := new(SourceMetadataPolicy)
+	mode := DatasetUpdateModeInline
+	err = mergeJsonInto(metadata,
+		&SourceMetadataPolicy{NamesRefreshMs: 60000, AuthTTLMs: 60000},
+		&SourceMetadataPolicyPatch{AuthTTLMs: Int64(0), DatasetUpdateMode: &mode})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMetadata := SourceMetadataPolicy{NamesRefreshMs: 60000, DatasetUpdateMode: mode}
+	if *metadata != wantMetadata {
+		t.Errorf("metadata policy = %+v, want %+v", *metadata, wantMetadata)
+	}
+}
+
+func TestPatchFormat(t *testing.T) {
+	text := &PhysicalDatasetFormat{Type: FormatTypeText, FieldDelimiter: ",", ExtractHeader: true}
+	tests := []struct {
+		name    string
+		patch   *PhysicalDatasetFormatPatch
+		want    PhysicalDatasetFormat
+		wantErr bool
+	}{
+		{
+			name:  "same type keeps options",
+			patch: &PhysicalDatasetFormatPatch{Type: String(FormatTypeText), Quote: String(`"`)},
+			want:  PhysicalDatasetFormat{Type: FormatTypeText, FieldDelimiter: ",", Quote: `"`, ExtractHeader: true},
+		},
+		{
+			name:  "type change drops options",
+			patch: &PhysicalDatasetFormatPatch{Type: String(FormatTypeParquet), AutoCorrectCorruptDates: Bool(true)},
+			want:  PhysicalDatasetFormat{Type: FormatTypeParquet, AutoCorrectCorruptDates: true},
+		},
+		{
+			name:    "invalid option for new type",
+			patch:   &PhysicalDatasetFormatPatch{Type: String(FormatTypeParquet), FieldDelimiter: String(";")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := patchFormat(text, tt.patch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("patchFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("patchFormat() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package dapi
+
+import (
+	"fmt"
+	"time"
+)
+
+type DatasetUpdateMode string
+
+const (
+	DatasetUpdateModePrefetch        DatasetUpdateMode = "PREFETCH"
+	DatasetUpdateModePrefetchQueried DatasetUpdateMode = "PREFETCH_QUERIED"
+	DatasetUpdateModeInline          DatasetUpdateMode = "INLINE"
+)
+
+type RefreshMethod string
+
+const (
+	RefreshMethodFull        RefreshMethod = "FULL"
+	RefreshMethodIncremental RefreshMethod = "INCREMENTAL"
+	RefreshMethodAuto        RefreshMethod = "AUTO"
+)
+
+// minPolicyPeriod is the shortest refresh or expiry period Dremio accepts.
+const minPolicyPeriod = time.Minute
+
+// Milliseconds converts d to the millisecond values used by Dremio policies.
+func Milliseconds(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
+
+func msDuration(ms int64) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
+
+func validatePeriod(name string, ms int64) error {
+	if ms == 0 {
+		return nil
+	}
+	if ms < Milliseconds(minPolicyPeriod) {
+		return fmt.Errorf("%s must be at least %s", name, minPolicyPeriod)
+	}
+	return nil
+}
+
+func validateExpiry(refreshName string, refreshMs int64, expireName string, expireMs int64) error {
+	if refreshMs != 0 && expireMs != 0 && expireMs < refreshMs {
+		return fmt.Errorf("%s must not be shorter than %s", expireName, refreshName)
+	}
+	return nil
+}
+
+func (p *SourceMetadataPolicy) AuthTTL() time.Duration {
+	return msDuration(p.AuthTTLMs)
+}
+
+func (p *SourceMetadataPolicy) SetAuthTTL(d time.Duration) {
+	p.AuthTTLMs = Milliseconds(d)
+}
+
+func (p *SourceMetadataPolicy) DatasetRefreshAfter() time.Duration {
+	return msDuration(p.DatasetRefreshAfterMs)
+}
+
+func (p *SourceMetadataPolicy) SetDatasetRefreshAfter(d time.Duration) {
+	p.DatasetRefreshAfterMs = Milliseconds(d)
+}
+
+func (p *SourceMetadataPolicy) DatasetExpireAfter() time.Duration {
+	return msDuration(p.DatasetExpireAfterMs)
+}
+
+func (p *SourceMetadataPolicy) SetDatasetExpireAfter(d time.Duration) {
+	p.DatasetExpireAfterMs = Milliseconds(d)
+}
+
+func (p *SourceMetadataPolicy) NamesRefresh() time.Duration {
+	return msDuration(p.NamesRefreshMs)
+}
+
+func (p *SourceMetadataPolicy) SetNamesRefresh(d time.Duration) {
+	p.NamesRefreshMs = Milliseconds(d)
+}
+
+func (p *SourceMetadataPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.AuthTTLMs < 0 {
+		return fmt.Errorf("authTTLMs must not be negative")
+	}
+	if err := validatePeriod("datasetRefreshAfterMs", p.DatasetRefreshAfterMs); err != nil {
+		return err
+	}
+	if err := validatePeriod("datasetExpireAfterMs", p.DatasetExpireAfterMs); err != nil {
+		return err
+	}
+	if err := validatePeriod("namesRefreshMs", p.NamesRefreshMs); err != nil {
+		return err
+	}
+	if err := validateExpiry("datasetRefreshAfterMs", p.DatasetRefreshAfterMs, "datasetExpireAfterMs", p.DatasetExpireAfterMs); err != nil {
+		return err
+	}
+	switch p.DatasetUpdateMode {
+	case "", DatasetUpdateModePrefetch, DatasetUpdateModePrefetchQueried, DatasetUpdateModeInline:
+		return nil
+	default:
+		return fmt.Errorf("unknown datasetUpdateMode %s", p.DatasetUpdateMode)
+	}
+}
+
+func (p *DatasetAccelerationRefreshPolicy) RefreshPeriod() time.Duration {
+	return msDuration(p.RefreshPeriodMs)
+}
+
+func (p *DatasetAccelerationRefreshPolicy) SetRefreshPeriod(d time.Duration) {
+	p.RefreshPeriodMs = Milliseconds(d)
+}
+
+func (p *DatasetAccelerationRefreshPolicy) GracePeriod() time.Duration {
+	return msDuration(p.GracePeriodMs)
+}
+
+func (p *DatasetAccelerationRefreshPolicy) SetGracePeriod(d time.Duration) {
+	p.GracePeriodMs = Milliseconds(d)
+}
+
+func (p *DatasetAccelerationRefreshPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if err := validatePeriod("refreshPeriodMs", p.RefreshPeriodMs); err != nil {
+		return err
+	}
+	if err := validatePeriod("gracePeriodMs", p.GracePeriodMs); err != nil {
+		return err
+	}
+	if err := validateExpiry("refreshPeriodMs", p.RefreshPeriodMs, "gracePeriodMs", p.GracePeriodMs); err != nil {
+		return err
+	}
+	switch p.Method {
+	case "", RefreshMethodFull, RefreshMethodAuto:
+		return nil
+	case RefreshMethodIncremental:
+		if p.RefreshField == "" {
+			return fmt.Errorf("refreshField is required for the %s refresh method", p.Method)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown refresh method %s", p.Method)
+	}
+}
+
+func (s *Source) AccelerationRefreshPeriod() time.Duration {
+	return msDuration(s.AccelerationRefreshPeriodMs)
+}
+
+func (s *Source) AccelerationGracePeriod() time.Duration {
+	return msDuration(s.AccelerationGracePeriodMs)
+}
+
+func validateSourcePolicies(source *Source) error {
+	if err := source.MetadataPolicy.Validate(); err != nil {
+		return err
+	}
+	if err := validatePeriod("accelerationRefreshPeriodMs", source.AccelerationRefreshPeriodMs); err != nil {
+		return err
+	}
+	if err := validatePeriod("accelerationGracePeriodMs", source.AccelerationGracePeriodMs); err != nil {
+		return err
+	}
+	return validateExpiry("accelerationRefreshPeriodMs", source.AccelerationRefreshPeriodMs, "accelerationGracePeriodMs", source.AccelerationGracePeriodMs)
+}
@@ -0,0 +1,82 @@
+package dapi
+
+import (
+	"errors"
+)
+
+type PromoteSpec struct {
+	Format                    *PhysicalDatasetFormat
+	AccelerationRefreshPolicy *DatasetAccelerationRefreshPolicy
+}
+
+// PromoteByPath promotes the file or folder at path in a source to a
+// physical dataset with the given format.
+func (c *Client) PromoteByPath(path []string, spec *PromoteSpec) (*PhysicalDataset, error) {
+	entity, err := c.GetCatalogEntityByPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return c.promote(entity, spec)
+}
+
+func (c *Client) promote(entity *CatalogEntity, spec *PromoteSpec) (*PhysicalDataset, error) {
+	if entity.EntityType == "dataset" {
+		return nil, errors.New("Catalog entity is already a dataset")
+	}
+	if entity.EntityType != "file" && entity.EntityType != "folder" {
+		return nil, errors.New("Catalog entity is not a file or folder")
+	}
+	return c.NewPhysicalDataset(entity.Id, &NewPhysicalDatasetSpec{
+		Path:                      entity.Path,
+		Format:                    spec.Format,
+		AccelerationRefreshPolicy: spec.AccelerationRefreshPolicy,
+	})
+}
+
+// UnpromotePhysicalDataset reverts a physical dataset back to the file or
+// folder it was promoted from.
+func (c *Client) UnpromotePhysicalDataset(id string) error {
+	if _, err := c.GetPhysicalDataset(id); err != nil {
+		return err
+	}
+	return c.DeleteCatalogItem(id)
+}
+
+func (c *Client) UnpromoteByPath(path []string) error {
+	entity, err := c.GetCatalogEntityByPath(path)
+	if err != nil {
+		return err
+	}
+	return c.UnpromotePhysicalDataset(entity.Id)
+}
+
+// EnsurePromoted promotes the file or folder at path, or updates the format
+// and refresh policy of the existing physical dataset when they differ from
+// the spec. The returned bool reports whether anything was changed.
+func (c *Client) EnsurePromoted(path []string, spec *PromoteSpec) (*PhysicalDataset, bool, error) {
+	entity, err := c.GetCatalogEntityByPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+	if entity.EntityType != "dataset" {
+		result, err := c.promote(entity, spec)
+		return result, err == nil, err
+	}
+	existing, err := c.GetPhysicalDataset(entity.Id)
+	if err != nil {
+		return nil, false, err
+	}
+	matches, err := specMatches(existing, PhysicalDataset{
+		Format:                    spec.Format,
+		AccelerationRefreshPolicy: spec.AccelerationRefreshPolicy,
+	}, "format", "accelerationRefreshPolicy")
+	if err != nil || matches {
+		return existing, false, err
+	}
+	result, err := c.UpdatePhysicalDataset(existing.Id, &UpdatePhysicalDatasetSpec{
+		Tag:                       existing.Tag,
+		Format:                    spec.Format,
+		AccelerationRefreshPolicy: spec.AccelerationRefreshPolicy,
+	})
+	return result, err == nil, err
+}
@@ -13,13 +13,13 @@ type Reflection struct {
 	Id                            string            `json:"id,omitempty"`
 	Tag                           string            `json:"tag,omitempty"`
 	Name                          string            `json:"name,omitempty"`
-	Enabled                       bool              `json:"enabled,omitempty"`
-	CreatedAt                     string            `json:"createdAt,omitempty"`
-	UpdatedAt                     string            `json:"updatedAt,omitempty"`
+	Enabled                       bool              `json:"enabled"`
+	CreatedAt                     *Timestamp        `json:"createdAt,omitempty"`
+	UpdatedAt                     *Timestamp        `json:"updatedAt,omitempty"`
 	Type                          string            `json:"type,omitempty"`
 	DatasetId                     string            `json:"datasetId,omitempty"`
-	CurrentSizeBytes              int               `json:"currentSizeBytes,omitempty"`
-	TotalSizeBytes                int               `json:"totalSizeBytes,omitempty"`
+	CurrentSizeBytes              int64             `json:"currentSizeBytes,omitempty"`
+	TotalSizeBytes                int64             `json:"totalSizeBytes,omitempty"`
 	Status                        ReflectionStatus  `json:"status,omitempty"`
 	DistributionFields            []ReflectionField `json:"distributionFields,omitempty"`
 	PartitionFields               []ReflectionField `json:"partitionFields,omitempty"`
@@ -28,12 +28,13 @@ type Reflection struct {
 }
 
 type ReflectionStatus struct {
-	Config       string `json:"config,omitempty"`
-	Refresh      string `json:"refresh,omitempty"`
-	Availability string `json:"availability,omitempty"`
-	FailureCount int    `json:"failureCount,omitempty"`
-	LastRefresh  string `json:"lastRefresh,omitempty"`
-	ExpiresAt    string `json:"expiresAt,omitempty"`
+	Config         ReflectionConfigStatus       `json:"config,omitempty"`
+	Refresh        ReflectionRefreshStatus      `json:"refresh,omitempty"`
+	Availability   ReflectionAvailabilityStatus `json:"availability,omitempty"`
+	CombinedStatus ReflectionCombinedStatus     `json:"combinedStatus,omitempty"`
+	FailureCount   int                          `json:"failureCount,omitempty"`
+	LastRefresh    *Timestamp                   `json:"lastRefresh,omitempty"`
+	ExpiresAt      *Timestamp                   `json:"expiresAt,omitempty"`
 }
 
 type RawReflection struct {
@@ -84,7 +85,11 @@ func (c *Client) updateReflection(id string, payload interface{}, result interfa
 		return err
 	}
 	path := fmt.Sprintf("/api/v3/reflection/%s", url.QueryEscape(id))
-	return c.request("PUT", path, bytes.NewBuffer(body), result)
+	err = c.request("PUT", path, bytes.NewBuffer(body), result)
+	if isAlreadyExists(err) {
+		return &ConflictError{Id: id, Err: err}
+	}
+	return err
 }
 
 func (c *Client) DeleteReflection(id string) error {
@@ -126,8 +131,8 @@ type RawReflectionSpec struct {
 	PartitionDistributionStrategy string
 }
 
-func (c *Client) NewRawReflection(datasetId string, spec *RawReflectionSpec) (*RawReflection, error) {
-	reflection := RawReflection{
+func (spec *RawReflectionSpec) reflection(datasetId string) RawReflection {
+	return RawReflection{
 		Reflection: Reflection{
 			EntityType:                    "reflection",
 			Name:                          spec.Name,
@@ -141,6 +146,10 @@ func (c *Client) NewRawReflection(datasetId string, spec *RawReflectionSpec) (*R
 		},
 		DisplayFields: spec.DisplayFields,
 	}
+}
+
+func (c *Client) NewRawReflection(datasetId string, spec *RawReflectionSpec) (*RawReflection, error) {
+	reflection := spec.reflection(datasetId)
 	result := new(RawReflection)
 	return result, c.newReflection(reflection, result)
 }
@@ -156,8 +165,8 @@ type AggregationReflectionSpec struct {
 	PartitionDistributionStrategy string
 }
 
-func (c *Client) NewAggregationReflection(datasetId string, spec *AggregationReflectionSpec) (*AggregationReflection, error) {
-	reflection := AggregationReflection{
+func (spec *AggregationReflectionSpec) reflection(datasetId string) AggregationReflection {
+	return AggregationReflection{
 		Reflection: Reflection{
 			EntityType:                    "reflection",
 			Name:                          spec.Name,
@@ -172,6 +181,10 @@ func (c *Client) NewAggregationReflection(datasetId string, spec *AggregationRef
 		DimensionFields: spec.DimensionFields,
 		MeasureFields:   spec.MeasureFields,
 	}
+}
+
+func (c *Client) NewAggregationReflection(datasetId string, spec *AggregationReflectionSpec) (*AggregationReflection, error) {
+	reflection := spec.reflection(datasetId)
 	result := new(AggregationReflection)
 	return result, c.newReflection(reflection, result)
 }
@@ -181,48 +194,278 @@ func (c *Client) UpdateRawReflection(id string, spec *RawReflectionSpec) (*RawRe
 	if err != nil {
 		return nil, err
 	}
+	reflection := spec.reflection(original.DatasetId)
+	reflection.Id = id
+	reflection.Tag = original.Tag
+	result := new(RawReflection)
+	return result, c.updateReflection(id, reflection, result)
+}
+
+func (c *Client) UpdateAggregationReflection(id string, spec *AggregationReflectionSpec) (*AggregationReflection, error) {
+	original, err := c.GetAggregationReflection(id)
+	if err != nil {
+		return nil, err
+	}
+	reflection := spec.reflection(original.DatasetId)
+	reflection.Id = id
+	reflection.Tag = original.Tag
+	result := new(AggregationReflection)
+	return result, c.updateReflection(id, reflection, result)
+}
+
+type reflectionSummary struct {
+	Reflection
+	DisplayFields   []ReflectionField                `json:"displayFields,omitempty"`
+	DimensionFields []ReflectionFieldWithGranularity `json:"dimensionFields,omitempty"`
+	MeasureFields   []ReflectionMeasureField         `json:"measureFields,omitempty"`
+}
+
+type reflectionListResponse struct {
+	Data []reflectionSummary `json:"data"`
+}
+
+func (r *reflectionSummary) entity() ReflectionEntity {
+	switch r.Type {
+	case "RAW":
+		return &RawReflection{
+			Reflection:    r.Reflection,
+			DisplayFields: r.DisplayFields,
+		}
+	case "AGGREGATION":
+		return &AggregationReflection{
+			Reflection:      r.Reflection,
+			DimensionFields: r.DimensionFields,
+			MeasureFields:   r.MeasureFields,
+		}
+	default:
+		return &r.Reflection
+	}
+}
+
+func (c *Client) listReflections(path string) ([]reflectionSummary, error) {
+	response := new(reflectionListResponse)
+	err := c.request("GET", path, nil, response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+func (c *Client) getDatasetReflections(datasetId string) ([]reflectionSummary, error) {
+	path := fmt.Sprintf("/api/v3/dataset/%s/reflection", url.QueryEscape(datasetId))
+	return c.listReflections(path)
+}
+
+// ReflectionEntity is implemented by *RawReflection and
+// *AggregationReflection, giving access to the settings they share.
+type ReflectionEntity interface {
+	ReflectionBase() *Reflection
+}
+
+func (r *Reflection) ReflectionBase() *Reflection {
+	return r
+}
+
+func toReflectionEntities(summaries []reflectionSummary) []ReflectionEntity {
+	result := make([]ReflectionEntity, len(summaries))
+	for i := range summaries {
+		result[i] = summaries[i].entity()
+	}
+	return result
+}
+
+// ListReflections returns every reflection as either a *RawReflection or an
+// *AggregationReflection.
+func (c *Client) ListReflections() ([]ReflectionEntity, error) {
+	reflections, err := c.listReflections("/api/v3/reflection")
+	if err != nil {
+		return nil, err
+	}
+	return toReflectionEntities(reflections), nil
+}
+
+// ListDatasetReflections returns the reflections defined on a dataset as
+// either a *RawReflection or an *AggregationReflection.
+func (c *Client) ListDatasetReflections(datasetId string) ([]ReflectionEntity, error) {
+	reflections, err := c.getDatasetReflections(datasetId)
+	if err != nil {
+		return nil, err
+	}
+	return toReflectionEntities(reflections), nil
+}
+
+// writable returns a copy of the reflection holding only the fields accepted
+// by an update.
+func (r *Reflection) writable() Reflection {
+	return Reflection{
+		EntityType:                    "reflection",
+		Id:                            r.Id,
+		Tag:                           r.Tag,
+		Name:                          r.Name,
+		Enabled:                       r.Enabled,
+		Type:                          r.Type,
+		DatasetId:                     r.DatasetId,
+		DistributionFields:            r.DistributionFields,
+		PartitionFields:               r.PartitionFields,
+		SortFields:                    r.SortFields,
+		PartitionDistributionStrategy: r.PartitionDistributionStrategy,
+	}
+}
+
+// PatchReflectionSpec holds the settings shared by raw and aggregation
+// reflections. Nil fields keep their current value.
+type PatchReflectionSpec struct {
+	Tag                           string
+	Name                          *string
+	Enabled                       *bool
+	DistributionFields            []ReflectionField
+	PartitionFields               []ReflectionField
+	SortFields                    []ReflectionField
+	PartitionDistributionStrategy *string
+}
+
+func (spec *PatchReflectionSpec) apply(original *Reflection) (Reflection, error) {
+	if err := checkTag(original.Id, spec.Tag, original.Tag); err != nil {
+		return Reflection{}, err
+	}
+	reflection := original.writable()
+	reflection.Tag = writeTag(spec.Tag, original.Tag)
+	if spec.Name != nil {
+		reflection.Name = *spec.Name
+	}
+	if spec.Enabled != nil {
+		reflection.Enabled = *spec.Enabled
+	}
+	if spec.DistributionFields != nil {
+		reflection.DistributionFields = spec.DistributionFields
+	}
+	if spec.PartitionFields != nil {
+		reflection.PartitionFields = spec.PartitionFields
+	}
+	if spec.SortFields != nil {
+		reflection.SortFields = spec.SortFields
+	}
+	if spec.PartitionDistributionStrategy != nil {
+		reflection.PartitionDistributionStrategy = *spec.PartitionDistributionStrategy
+	}
+	return reflection, nil
+}
+
+type PatchRawReflectionSpec struct {
+	PatchReflectionSpec
+	DisplayFields []ReflectionField
+}
+
+func (c *Client) PatchRawReflection(id string, spec *PatchRawReflectionSpec) (*RawReflection, error) {
+	original, err := c.GetRawReflection(id)
+	if err != nil {
+		return nil, err
+	}
+	base, err := spec.apply(&original.Reflection)
+	if err != nil {
+		return nil, err
+	}
 	reflection := RawReflection{
-		Reflection: Reflection{
-			EntityType:                    "reflection",
-			Id:                            id,
-			Tag:                           original.Tag,
-			Name:                          spec.Name,
-			Enabled:                       spec.Enabled,
-			Type:                          "RAW",
-			DatasetId:                     original.DatasetId,
-			DistributionFields:            spec.DistributionFields,
-			PartitionFields:               spec.PartitionFields,
-			SortFields:                    spec.SortFields,
-			PartitionDistributionStrategy: spec.PartitionDistributionStrategy,
-		},
-		DisplayFields: spec.DisplayFields,
+		Reflection:    base,
+		DisplayFields: original.DisplayFields,
+	}
+	if spec.DisplayFields != nil {
+		reflection.DisplayFields = spec.DisplayFields
 	}
 	result := new(RawReflection)
 	return result, c.updateReflection(id, reflection, result)
 }
 
-func (c *Client) UpdateAggregationReflection(id string, spec *AggregationReflectionSpec) (*AggregationReflection, error) {
+type PatchAggregationReflectionSpec struct {
+	PatchReflectionSpec
+	DimensionFields []ReflectionFieldWithGranularity
+	MeasureFields   []ReflectionMeasureField
+}
+
+func (c *Client) PatchAggregationReflection(id string, spec *PatchAggregationReflectionSpec) (*AggregationReflection, error) {
 	original, err := c.GetAggregationReflection(id)
 	if err != nil {
 		return nil, err
 	}
+	base, err := spec.apply(&original.Reflection)
+	if err != nil {
+		return nil, err
+	}
 	reflection := AggregationReflection{
-		Reflection: Reflection{
-			EntityType:                    "reflection",
-			Id:                            id,
-			Tag:                           original.Tag,
-			Name:                          spec.Name,
-			Enabled:                       spec.Enabled,
-			Type:                          "AGGREGATION",
-			DatasetId:                     original.DatasetId,
-			DistributionFields:            spec.DistributionFields,
-			PartitionFields:               spec.PartitionFields,
-			SortFields:                    spec.SortFields,
-			PartitionDistributionStrategy: spec.PartitionDistributionStrategy,
-		},
-		DimensionFields: spec.DimensionFields,
-		MeasureFields:   spec.MeasureFields,
+		Reflection:      base,
+		DimensionFields: original.DimensionFields,
+		MeasureFields:   original.MeasureFields,
+	}
+	if spec.DimensionFields != nil {
+		reflection.DimensionFields = spec.DimensionFields
+	}
+	if spec.MeasureFields != nil {
+		reflection.MeasureFields = spec.MeasureFields
 	}
 	result := new(AggregationReflection)
 	return result, c.updateReflection(id, reflection, result)
 }
+
+type ReflectionRecommendations struct {
+	Raw         []*RawReflectionSpec
+	Aggregation []*AggregationReflectionSpec
+}
+
+// GetReflectionRecommendations asks Dremio to suggest raw and aggregation
+// reflections for a dataset. The returned specs can be passed directly to
+// NewRawReflection and NewAggregationReflection.
+func (c *Client) GetReflectionRecommendations(datasetId string) (*ReflectionRecommendations, error) {
+	raw, err := c.getReflectionRecommendations(datasetId, "raw")
+	if err != nil {
+		return nil, err
+	}
+	aggregation, err := c.getReflectionRecommendations(datasetId, "agg")
+	if err != nil {
+		return nil, err
+	}
+	result := new(ReflectionRecommendations)
+	for i, r := range raw {
+		result.Raw = append(result.Raw, &RawReflectionSpec{
+			Name:                          recommendationName(r.Name, "Raw", i),
+			Enabled:                       true,
+			DisplayFields:                 r.DisplayFields,
+			DistributionFields:            r.DistributionFields,
+			PartitionFields:               r.PartitionFields,
+			SortFields:                    r.SortFields,
+			PartitionDistributionStrategy: r.PartitionDistributionStrategy,
+		})
+	}
+	for i, r := range aggregation {
+		result.Aggregation = append(result.Aggregation, &AggregationReflectionSpec{
+			Name:                          recommendationName(r.Name, "Aggregation", i),
+			Enabled:                       true,
+			DimensionFields:               r.DimensionFields,
+			MeasureFields:                 r.MeasureFields,
+			DistributionFields:            r.DistributionFields,
+			PartitionFields:               r.PartitionFields,
+			SortFields:                    r.SortFields,
+			PartitionDistributionStrategy: r.PartitionDistributionStrategy,
+		})
+	}
+	return result, nil
+}
+
+func (c *Client) getReflectionRecommendations(datasetId string, reflectionType string) ([]reflectionSummary, error) {
+	response := new(reflectionListResponse)
+	path := fmt.Sprintf("/api/v3/dataset/%s/reflection/recommendation/%s", url.QueryEscape(datasetId), reflectionType)
+	err := c.request("POST", path, nil, response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+func recommendationName(name string, reflectionType string, index int) string {
+	if name != "" {
+		return name
+	}
+	if index == 0 {
+		return fmt.Sprintf("Recommended %s Reflection", reflectionType)
+	}
+	return fmt.Sprintf("Recommended %s Reflection %d", reflectionType, index+1)
+}
@@ -0,0 +1,138 @@
+package dapi
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+type ReflectionConfigStatus string
+
+const (
+	ReflectionConfigOk      ReflectionConfigStatus = "OK"
+	ReflectionConfigInvalid ReflectionConfigStatus = "INVALID"
+)
+
+type ReflectionRefreshStatus string
+
+const (
+	ReflectionRefreshScheduled ReflectionRefreshStatus = "SCHEDULED"
+	ReflectionRefreshRunning   ReflectionRefreshStatus = "RUNNING"
+	ReflectionRefreshGivenUp   ReflectionRefreshStatus = "GIVEN_UP"
+	ReflectionRefreshManual    ReflectionRefreshStatus = "MANUAL"
+)
+
+type ReflectionAvailabilityStatus string
+
+const (
+	ReflectionAvailabilityNone       ReflectionAvailabilityStatus = "NONE"
+	ReflectionAvailabilityIncomplete ReflectionAvailabilityStatus = "INCOMPLETE"
+	ReflectionAvailabilityExpired    ReflectionAvailabilityStatus = "EXPIRED"
+	ReflectionAvailabilityAvailable  ReflectionAvailabilityStatus = "AVAILABLE"
+)
+
+type ReflectionCombinedStatus string
+
+const (
+	ReflectionCanAccelerate             ReflectionCombinedStatus = "CAN_ACCELERATE"
+	ReflectionCanAccelerateWithFailures ReflectionCombinedStatus = "CAN_ACCELERATE_WITH_FAILURES"
+	ReflectionCannotAccelerateScheduled ReflectionCombinedStatus = "CANNOT_ACCELERATE_SCHEDULED"
+	ReflectionCannotAccelerateManual    ReflectionCombinedStatus = "CANNOT_ACCELERATE_MANUAL"
+	ReflectionRefreshing                ReflectionCombinedStatus = "REFRESHING"
+	ReflectionDisabled                  ReflectionCombinedStatus = "DISABLED"
+	ReflectionExpired                   ReflectionCombinedStatus = "EXPIRED"
+	ReflectionFailed                    ReflectionCombinedStatus = "FAILED"
+	ReflectionInvalid                   ReflectionCombinedStatus = "INVALID"
+	ReflectionIncomplete                ReflectionCombinedStatus = "INCOMPLETE"
+)
+
+func (s *ReflectionStatus) Available() bool {
+	return s.Availability == ReflectionAvailabilityAvailable
+}
+
+// Failed reports whether the reflection will not become available without
+// intervention: Dremio gave up building it, it is disabled, or it is not
+// available and only refreshes manually. A reflection whose refreshes failed
+// but that is still scheduled is retried by Dremio until the refresh status
+// becomes GIVEN_UP, so it has not failed.
+func (s *ReflectionStatus) Failed() bool {
+	return s.refreshFailed() || s.awaitsManualRefresh()
+}
+
+// refreshFailed reports whether a refresh of the reflection cannot succeed.
+func (s *ReflectionStatus) refreshFailed() bool {
+	if s.Config == ReflectionConfigInvalid || s.Refresh == ReflectionRefreshGivenUp {
+		return true
+	}
+	switch s.CombinedStatus {
+	case ReflectionFailed, ReflectionInvalid, ReflectionDisabled:
+		return true
+	}
+	return false
+}
+
+func (s *ReflectionStatus) awaitsManualRefresh() bool {
+	return s.CombinedStatus == ReflectionCannotAccelerateManual ||
+		(s.Refresh == ReflectionRefreshManual && !s.Available())
+}
+
+type ReflectionFailedError struct {
+	Id           string
+	Status       ReflectionStatus
+	FailureCount int
+	Message      string
+}
+
+func (e *ReflectionFailedError) Error() string {
+	return fmt.Sprintf("reflection %s failed after %d attempts: %s", e.Id, e.FailureCount, e.Message)
+}
+
+func reflectionFailure(id string, status ReflectionStatus) *ReflectionFailedError {
+	message := fmt.Sprintf("config %s, refresh %s, availability %s", status.Config, status.Refresh, status.Availability)
+	if status.CombinedStatus != "" {
+		message = fmt.Sprintf("status %s, %s", status.CombinedStatus, message)
+	}
+	return &ReflectionFailedError{
+		Id:           id,
+		Status:       status,
+		FailureCount: status.FailureCount,
+		Message:      message,
+	}
+}
+
+// WaitForReflection polls the reflection until it is available. It fails
+// with a *ReflectionFailedError when the reflection has failed as described
+// on ReflectionStatus.Failed.
+// Failed refreshes that Dremio will retry are logged and polling continues;
+// if the context ends first, the returned error includes the failure count.
+func (c *Client) WaitForReflection(ctx context.Context, id string, opts *WaitOptions) (*Reflection, error) {
+	var reflection *Reflection
+	failures := 0
+	err := poll(ctx, opts.pollInterval(), func() (bool, error) {
+		reflection = new(Reflection)
+		if err := c.getReflection(id, reflection); err != nil {
+			return false, err
+		}
+		if reflection.Status.Failed() {
+			return false, reflectionFailure(id, reflection.Status)
+		}
+		failures = logReflectionFailures(id, reflection.Status, failures)
+		return reflection.Status.Available(), nil
+	})
+	if err != nil && failures > 0 && ctx.Err() != nil {
+		return nil, fmt.Errorf("reflection %s is not available after %d failed refreshes: %w", id, failures, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reflection, nil
+}
+
+// logReflectionFailures logs the failure count of a reflection when it has
+// grown past previous and returns the current count.
+func logReflectionFailures(id string, status ReflectionStatus, previous int) int {
+	if status.FailureCount > previous {
+		log.Printf("Reflection %s refresh failed %d times, status %s; Dremio will retry", id, status.FailureCount, status.CombinedStatus)
+	}
+	return status.FailureCount
+}
@@ -0,0 +1,31 @@
+package dapi
+
+import "testing"
+
+func TestReflectionStatusFailed(t *testing.T) {
+	tests := []struct {
+		name   string
+		status ReflectionStatus
+		failed bool
+	}{
+		{name: "available", status: ReflectionStatus{Config: ReflectionConfigOk, Refresh: ReflectionRefreshScheduled, Availability: ReflectionAvailabilityAvailable, CombinedStatus: ReflectionCanAccelerate}},
+		{name: "retrying after failure", status: ReflectionStatus{Config: ReflectionConfigOk, Refresh: ReflectionRefreshScheduled, CombinedStatus: ReflectionCannotAccelerateScheduled, FailureCount: 2}},
+		{name: "available with failures", status: ReflectionStatus{Refresh: ReflectionRefreshScheduled, CombinedStatus: ReflectionCanAccelerateWithFailures, FailureCount: 1}},
+		{name: "refreshing", status: ReflectionStatus{Refresh: ReflectionRefreshRunning, CombinedStatus: ReflectionRefreshing}},
+		{name: "manual and available", status: ReflectionStatus{Refresh: ReflectionRefreshManual, Availability: ReflectionAvailabilityAvailable, CombinedStatus: ReflectionCanAccelerate}},
+		{name: "manual and not available", status: ReflectionStatus{Refresh: ReflectionRefreshManual, Availability: ReflectionAvailabilityNone}, failed: true},
+		{name: "cannot accelerate manual", status: ReflectionStatus{CombinedStatus: ReflectionCannotAccelerateManual}, failed: true},
+		{name: "disabled", status: ReflectionStatus{CombinedStatus: ReflectionDisabled}, failed: true},
+		{name: "given up", status: ReflectionStatus{Refresh: ReflectionRefreshGivenUp, FailureCount: 3}, failed: true},
+		{name: "invalid config", status: ReflectionStatus{Config: ReflectionConfigInvalid}, failed: true},
+		{name: "combined failed", status: ReflectionStatus{CombinedStatus: ReflectionFailed}, failed: true},
+		{name: "combined invalid", status: ReflectionStatus{CombinedStatus: ReflectionInvalid}, failed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.Failed(); got != tt.failed {
+				t.Errorf("Failed() = %v, want %v", got, tt.failed)
+			}
+		})
+	}
+}
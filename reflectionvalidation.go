@@ -0,0 +1,174 @@
+package dapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	MeasureMin                      = "MIN"
+	MeasureMax                      = "MAX"
+	MeasureSum                      = "SUM"
+	MeasureCount                    = "COUNT"
+	MeasureApproximateCountDistinct = "APPROXIMATE_COUNT_DISTINCT"
+)
+
+const (
+	GranularityNormal = "NORMAL"
+	GranularityDate   = "DATE"
+)
+
+type ReflectionValidationError struct {
+	Problems []string
+}
+
+func (e *ReflectionValidationError) Error() string {
+	return "invalid reflection: " + strings.Join(e.Problems, "; ")
+}
+
+type fieldKind int
+
+const (
+	fieldKindOther fieldKind = iota
+	fieldKindNumeric
+	fieldKindTemporal
+	fieldKindComplex
+)
+
+func kindOfField(t DatasetFieldType) fieldKind {
+	switch strings.ToUpper(t.Name) {
+	case "TINYINT", "SMALLINT", "INT", "INTEGER", "BIGINT", "FLOAT", "DOUBLE", "DECIMAL":
+		return fieldKindNumeric
+	case "DATE", "TIME", "TIMESTAMP":
+		return fieldKindTemporal
+	case "STRUCT", "LIST", "MAP", "UNION", "OTHER":
+		return fieldKindComplex
+	default:
+		return fieldKindOther
+	}
+}
+
+type reflectionValidator struct {
+	fields   map[string]DatasetField
+	problems []string
+}
+
+func (c *Client) newReflectionValidator(datasetId string) (*reflectionValidator, error) {
+	dataset, err := c.GetDataset(datasetId)
+	if err != nil {
+		return nil, err
+	}
+	v := &reflectionValidator{fields: make(map[string]DatasetField, len(dataset.Fields))}
+	for _, f := range dataset.Fields {
+		v.fields[strings.ToLower(f.Name)] = f
+	}
+	return v, nil
+}
+
+func (v *reflectionValidator) addProblem(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *reflectionValidator) field(role string, name string) (DatasetField, bool) {
+	f, ok := v.fields[strings.ToLower(name)]
+	if !ok {
+		v.addProblem("%s field %s does not exist in the dataset", role, name)
+	}
+	return f, ok
+}
+
+func (v *reflectionValidator) checkFields(role string, fields []ReflectionField, allowComplex bool) {
+	for _, rf := range fields {
+		f, ok := v.field(role, rf.Name)
+		if ok && !allowComplex && kindOfField(f.Type) == fieldKindComplex {
+			v.addProblem("%s field %s has unsupported type %s", role, rf.Name, f.Type.Name)
+		}
+	}
+}
+
+func (v *reflectionValidator) checkShared(distribution []ReflectionField, partition []ReflectionField, sort []ReflectionField) {
+	v.checkFields("distribution", distribution, false)
+	v.checkFields("partition", partition, false)
+	v.checkFields("sort", sort, false)
+}
+
+func (v *reflectionValidator) checkDimensions(fields []ReflectionFieldWithGranularity) {
+	for _, d := range fields {
+		f, ok := v.field("dimension", d.Name)
+		if !ok {
+			continue
+		}
+		kind := kindOfField(f.Type)
+		if kind == fieldKindComplex {
+			v.addProblem("dimension field %s has unsupported type %s", d.Name, f.Type.Name)
+		}
+		switch d.Granularity {
+		case "", GranularityNormal:
+		case GranularityDate:
+			if kind != fieldKindTemporal {
+				v.addProblem("dimension field %s of type %s does not support %s granularity", d.Name, f.Type.Name, d.Granularity)
+			}
+		default:
+			v.addProblem("dimension field %s has unknown granularity %s", d.Name, d.Granularity)
+		}
+	}
+}
+
+func (v *reflectionValidator) checkMeasures(fields []ReflectionMeasureField) {
+	for _, m := range fields {
+		f, ok := v.field("measure", m.Name)
+		if !ok {
+			continue
+		}
+		kind := kindOfField(f.Type)
+		if kind == fieldKindComplex {
+			v.addProblem("measure field %s has unsupported type %s", m.Name, f.Type.Name)
+			continue
+		}
+		for _, t := range m.MeasureTypeList {
+			switch t {
+			case MeasureMin, MeasureMax, MeasureCount, MeasureApproximateCountDistinct:
+			case MeasureSum:
+				if kind != fieldKindNumeric {
+					v.addProblem("measure field %s of type %s does not support %s", m.Name, f.Type.Name, t)
+				}
+			default:
+				v.addProblem("measure field %s has unknown measure type %s", m.Name, t)
+			}
+		}
+	}
+}
+
+func (v *reflectionValidator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ReflectionValidationError{Problems: v.problems}
+}
+
+// ValidateRawReflectionSpec checks the fields of spec against the schema of
+// the dataset, returning a *ReflectionValidationError listing every problem.
+func (c *Client) ValidateRawReflectionSpec(datasetId string, spec *RawReflectionSpec) error {
+	v, err := c.newReflectionValidator(datasetId)
+	if err != nil {
+		return err
+	}
+	v.checkFields("display", spec.DisplayFields, true)
+	v.checkShared(spec.DistributionFields, spec.PartitionFields, spec.SortFields)
+	return v.err()
+}
+
+// ValidateAggregationReflectionSpec checks the fields of spec against the
+// schema of the dataset, including that measure types and granularities suit
+// the column types. It returns a *ReflectionValidationError listing every
+// problem.
+func (c *Client) ValidateAggregationReflectionSpec(datasetId string, spec *AggregationReflectionSpec) error {
+	v, err := c.newReflectionValidator(datasetId)
+	if err != nil {
+		return err
+	}
+	v.checkDimensions(spec.DimensionFields)
+	v.checkMeasures(spec.MeasureFields)
+	v.checkShared(spec.DistributionFields, spec.PartitionFields, spec.SortFields)
+	return v.err()
+}
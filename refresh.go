@@ -0,0 +1,189 @@
+package dapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type ReflectionRefreshOptions struct {
+	// Wait blocks until the LastRefresh of every enabled reflection affected
+	// by the refresh has advanced: the reflections on the refreshed physical
+	// datasets and on every dataset that depends on them.
+	Wait        bool
+	WaitOptions *WaitOptions
+}
+
+func (o *ReflectionRefreshOptions) wait() bool {
+	return o != nil && o.Wait
+}
+
+func (o *ReflectionRefreshOptions) waitOptions() *WaitOptions {
+	if o == nil {
+		return nil
+	}
+	return o.WaitOptions
+}
+
+type datasetGraph struct {
+	Parents  []CatalogEntitySummary `json:"parents"`
+	Children []CatalogEntitySummary `json:"children"`
+}
+
+func (c *Client) getDatasetGraph(id string) (*datasetGraph, error) {
+	response := new(datasetGraph)
+	path := fmt.Sprintf("/api/v3/catalog/%s/graph", url.QueryEscape(id))
+	err := c.request("GET", path, nil, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (c *Client) triggerReflectionRefresh(pdsId string) error {
+	path := fmt.Sprintf("/api/v3/catalog/%s/refresh", url.QueryEscape(pdsId))
+	return c.request("POST", path, nil, nil)
+}
+
+// RefreshReflectionsDependingOn refreshes every reflection that depends on
+// the physical dataset.
+func (c *Client) RefreshReflectionsDependingOn(ctx context.Context, pdsId string, opts *ReflectionRefreshOptions) error {
+	return c.refreshReflections(ctx, []string{pdsId}, opts)
+}
+
+// RefreshDatasetReflections refreshes the reflections of a dataset. Dremio
+// refreshes reflections from their physical datasets, so for a virtual
+// dataset the refresh is triggered on every physical dataset it reads from.
+func (c *Client) RefreshDatasetReflections(ctx context.Context, datasetId string, opts *ReflectionRefreshOptions) error {
+	dataset, err := c.GetDataset(datasetId)
+	if err != nil {
+		return err
+	}
+	if dataset.Type == "PHYSICAL_DATASET" {
+		return c.refreshReflections(ctx, []string{datasetId}, opts)
+	}
+	pdsIds, err := c.physicalAncestors(datasetId)
+	if err != nil {
+		return err
+	}
+	if len(pdsIds) == 0 {
+		return errors.New("Dataset does not depend on any physical dataset")
+	}
+	return c.refreshReflections(ctx, pdsIds, opts)
+}
+
+func (c *Client) physicalAncestors(datasetId string) ([]string, error) {
+	var result []string
+	seen := map[string]bool{datasetId: true}
+	pending := []string{datasetId}
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		graph, err := c.getDatasetGraph(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range graph.Parents {
+			if seen[parent.Id] {
+				continue
+			}
+			seen[parent.Id] = true
+			if parent.DatasetType == "VIRTUAL" {
+				pending = append(pending, parent.Id)
+			} else {
+				result = append(result, parent.Id)
+			}
+		}
+	}
+	return result, nil
+}
+
+// downstreamDatasets returns the given datasets followed by every dataset
+// that depends on them, directly or through other datasets.
+func (c *Client) downstreamDatasets(datasetIds []string) ([]string, error) {
+	result := append([]string{}, datasetIds...)
+	seen := make(map[string]bool, len(datasetIds))
+	for _, id := range datasetIds {
+		seen[id] = true
+	}
+	for i := 0; i < len(result); i++ {
+		graph, err := c.getDatasetGraph(result[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range graph.Children {
+			if !seen[child.Id] {
+				seen[child.Id] = true
+				result = append(result, child.Id)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) refreshReflections(ctx context.Context, pdsIds []string, opts *ReflectionRefreshOptions) error {
+	var before map[string]time.Time
+	if opts.wait() {
+		datasetIds, err := c.downstreamDatasets(pdsIds)
+		if err != nil {
+			return err
+		}
+		before, err = c.lastRefreshes(datasetIds)
+		if err != nil {
+			return err
+		}
+	}
+	for _, id := range pdsIds {
+		if err := c.triggerReflectionRefresh(id); err != nil {
+			return err
+		}
+	}
+	if !opts.wait() {
+		return nil
+	}
+	return c.waitForRefreshes(ctx, before, opts.waitOptions())
+}
+
+func (c *Client) lastRefreshes(datasetIds []string) (map[string]time.Time, error) {
+	result := make(map[string]time.Time)
+	for _, id := range datasetIds {
+		reflections, err := c.getDatasetReflections(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range reflections {
+			if r.Enabled {
+				result[r.Id] = r.Status.LastRefresh.AsTime()
+			}
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) waitForRefreshes(ctx context.Context, before map[string]time.Time, opts *WaitOptions) error {
+	pending := make(map[string]time.Time, len(before))
+	for id, lastRefresh := range before {
+		pending[id] = lastRefresh
+	}
+	failures := make(map[string]int)
+	return poll(ctx, opts.pollInterval(), func() (bool, error) {
+		for id, lastRefresh := range pending {
+			reflection := new(Reflection)
+			if err := c.getReflection(id, reflection); err != nil {
+				return false, err
+			}
+			// The refresh was just triggered, so reflections that only
+			// refresh manually are expected to become available.
+			if reflection.Status.refreshFailed() {
+				return false, reflectionFailure(id, reflection.Status)
+			}
+			failures[id] = logReflectionFailures(id, reflection.Status, failures[id])
+			if reflection.Status.LastRefresh.AsTime().After(lastRefresh) {
+				delete(pending, id)
+			}
+		}
+		return len(pending) == 0, nil
+	})
+}
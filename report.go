@@ -0,0 +1,211 @@
+package dapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ReflectionUsage struct {
+	Id               string    `json:"id"`
+	Name             string    `json:"name"`
+	Type             string    `json:"type"`
+	Enabled          bool      `json:"enabled"`
+	DatasetId        string    `json:"datasetId"`
+	DatasetPath      []string  `json:"datasetPath,omitempty"`
+	Space            string    `json:"space,omitempty"`
+	Source           string    `json:"source,omitempty"`
+	CurrentSizeBytes int64     `json:"currentSizeBytes"`
+	TotalSizeBytes   int64     `json:"totalSizeBytes"`
+	FailureCount     int       `json:"failureCount"`
+	LastRefresh      time.Time `json:"lastRefresh"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	Stale            bool      `json:"stale"`
+}
+
+type ReflectionUsageGroup struct {
+	Key              string    `json:"key"`
+	Reflections      int       `json:"reflections"`
+	CurrentSizeBytes int64     `json:"currentSizeBytes"`
+	TotalSizeBytes   int64     `json:"totalSizeBytes"`
+	FailureCount     int       `json:"failureCount"`
+	Stale            int       `json:"stale"`
+	OldestRefresh    time.Time `json:"oldestRefresh"`
+}
+
+type ReflectionUsageGroups []ReflectionUsageGroup
+
+type ReflectionReport struct {
+	GeneratedAt time.Time             `json:"generatedAt"`
+	Reflections []ReflectionUsage     `json:"reflections"`
+	ByDataset   ReflectionUsageGroups `json:"byDataset"`
+	BySpace     ReflectionUsageGroups `json:"bySpace"`
+	BySource    ReflectionUsageGroups `json:"bySource"`
+}
+
+// GetReflectionReport collects the size, failures and staleness of every
+// reflection and groups them by dataset, space and source.
+func (c *Client) GetReflectionReport() (*ReflectionReport, error) {
+	reflections, err := c.ListReflections()
+	if err != nil {
+		return nil, err
+	}
+	roots, err := c.GetRootCatalogSummary()
+	if err != nil {
+		return nil, err
+	}
+	rootTypes := make(map[string]string, len(roots))
+	for _, r := range roots {
+		if len(r.Path) > 0 {
+			rootTypes[r.Path[0]] = r.ContainerType
+		}
+	}
+
+	now := time.Now()
+	report := &ReflectionReport{GeneratedAt: now}
+	datasetPaths := make(map[string][]string)
+	for _, entity := range reflections {
+		r := entity.ReflectionBase()
+		path, ok := datasetPaths[r.DatasetId]
+		if !ok {
+			dataset, err := c.GetCatalogEntityById(r.DatasetId)
+			if err != nil {
+				return nil, err
+			}
+			path = dataset.Path
+			datasetPaths[r.DatasetId] = path
+		}
+		usage := ReflectionUsage{
+			Id:               r.Id,
+			Name:             r.Name,
+			Type:             r.Type,
+			Enabled:          r.Enabled,
+			DatasetId:        r.DatasetId,
+			DatasetPath:      path,
+			CurrentSizeBytes: r.CurrentSizeBytes,
+			TotalSizeBytes:   r.TotalSizeBytes,
+			FailureCount:     r.Status.FailureCount,
+			LastRefresh:      r.Status.LastRefresh.AsTime(),
+			ExpiresAt:        r.Status.ExpiresAt.AsTime(),
+		}
+		usage.Stale = r.Status.Availability == ReflectionAvailabilityExpired ||
+			(!usage.ExpiresAt.IsZero() && usage.ExpiresAt.Before(now))
+		if len(path) > 0 {
+			if rootTypes[path[0]] == "SOURCE" {
+				usage.Source = path[0]
+			} else {
+				usage.Space = path[0]
+			}
+		}
+		report.Reflections = append(report.Reflections, usage)
+	}
+
+	report.ByDataset = groupReflectionUsage(report.Reflections, func(u *ReflectionUsage) string {
+		return strings.Join(u.DatasetPath, ".")
+	})
+	report.BySpace = groupReflectionUsage(report.Reflections, func(u *ReflectionUsage) string {
+		return u.Space
+	})
+	report.BySource = groupReflectionUsage(report.Reflections, func(u *ReflectionUsage) string {
+		return u.Source
+	})
+	return report, nil
+}
+
+func groupReflectionUsage(usages []ReflectionUsage, key func(*ReflectionUsage) string) ReflectionUsageGroups {
+	groups := make(map[string]*ReflectionUsageGroup)
+	for i := range usages {
+		u := &usages[i]
+		k := key(u)
+		if k == "" {
+			continue
+		}
+		g, ok := groups[k]
+		if !ok {
+			g = &ReflectionUsageGroup{Key: k}
+			groups[k] = g
+		}
+		g.Reflections++
+		g.CurrentSizeBytes += u.CurrentSizeBytes
+		g.TotalSizeBytes += u.TotalSizeBytes
+		g.FailureCount += u.FailureCount
+		if u.Stale {
+			g.Stale++
+		}
+		if !u.LastRefresh.IsZero() && (g.OldestRefresh.IsZero() || u.LastRefresh.Before(g.OldestRefresh)) {
+			g.OldestRefresh = u.LastRefresh
+		}
+	}
+	result := make(ReflectionUsageGroups, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TotalSizeBytes > result[j].TotalSizeBytes
+	})
+	return result
+}
+
+func (r *ReflectionReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes one row per reflection.
+func (r *ReflectionReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{
+		"id", "name", "type", "enabled", "dataset", "space", "source",
+		"current_size_bytes", "total_size_bytes", "failure_count", "last_refresh", "expires_at", "stale",
+	})
+	for _, u := range r.Reflections {
+		writer.Write([]string{
+			u.Id,
+			u.Name,
+			u.Type,
+			strconv.FormatBool(u.Enabled),
+			strings.Join(u.DatasetPath, "."),
+			u.Space,
+			u.Source,
+			strconv.FormatInt(u.CurrentSizeBytes, 10),
+			strconv.FormatInt(u.TotalSizeBytes, 10),
+			strconv.Itoa(u.FailureCount),
+			formatReportTime(u.LastRefresh),
+			formatReportTime(u.ExpiresAt),
+			strconv.FormatBool(u.Stale),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteCSV writes one row per group.
+func (g ReflectionUsageGroups) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"key", "reflections", "current_size_bytes", "total_size_bytes", "failure_count", "stale", "oldest_refresh"})
+	for _, group := range g {
+		writer.Write([]string{
+			group.Key,
+			strconv.Itoa(group.Reflections),
+			strconv.FormatInt(group.CurrentSizeBytes, 10),
+			strconv.FormatInt(group.TotalSizeBytes, 10),
+			strconv.Itoa(group.FailureCount),
+			strconv.Itoa(group.Stale),
+			formatReportTime(group.OldestRefresh),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatReportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
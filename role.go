@@ -0,0 +1,168 @@
+package dapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+const (
+	MemberTypeUser = "USER"
+	MemberTypeRole = "ROLE"
+)
+
+type Role struct {
+	Id          string          `json:"id,omitempty"`
+	Name        string          `json:"name,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Type        string          `json:"type,omitempty"`
+	Tag         string          `json:"tag,omitempty"`
+	Roles       []RoleReference `json:"roles,omitempty"`
+}
+
+type RoleReference struct {
+	Id   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Type string `json:"type,omitempty"`
+}
+
+type RoleMember struct {
+	Id   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+}
+
+type roleMemberListResponse struct {
+	Data []RoleMember `json:"data"`
+}
+
+type roleMemberUpdate struct {
+	Add    []RoleMember `json:"add,omitempty"`
+	Remove []RoleMember `json:"remove,omitempty"`
+}
+
+func (c *Client) GetRole(id string) (*Role, error) {
+	response := new(Role)
+	path := fmt.Sprintf("/api/v3/role/%s", url.QueryEscape(id))
+	err := c.request("GET", path, nil, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (c *Client) GetRoleByName(name string) (*Role, error) {
+	response := new(Role)
+	path := fmt.Sprintf("/api/v3/role/by-name/%s", url.PathEscape(name))
+	err := c.request("GET", path, nil, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+type NewRoleSpec struct {
+	Name        string
+	Description string
+}
+
+func (c *Client) NewRole(spec *NewRoleSpec) (*Role, error) {
+	body, err := json.Marshal(Role{
+		Name:        spec.Name,
+		Description: spec.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := new(Role)
+	err = c.request("POST", "/api/v3/role", bytes.NewBuffer(body), result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type UpdateRoleSpec struct {
+	Tag         string
+	Name        string
+	Description string
+}
+
+func (c *Client) UpdateRole(id string, spec *UpdateRoleSpec) (*Role, error) {
+	original, err := c.GetRole(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTag(id, spec.Tag, original.Tag); err != nil {
+		return nil, err
+	}
+	role := *original
+	role.Tag = writeTag(spec.Tag, original.Tag)
+	role.Name = spec.Name
+	role.Description = spec.Description
+	body, err := json.Marshal(role)
+	if err != nil {
+		return nil, err
+	}
+	result := new(Role)
+	path := fmt.Sprintf("/api/v3/role/%s", url.QueryEscape(id))
+	err = c.request("PUT", path, bytes.NewBuffer(body), result)
+	if isAlreadyExists(err) {
+		return nil, &ConflictError{Id: id, Err: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) DeleteRole(id string) error {
+	path := fmt.Sprintf("/api/v3/role/%s", url.QueryEscape(id))
+	return c.request("DELETE", path, nil, nil)
+}
+
+// ListRoleMembers returns the users and child roles that are members of the
+// role.
+func (c *Client) ListRoleMembers(id string) ([]RoleMember, error) {
+	response := new(roleMemberListResponse)
+	path := fmt.Sprintf("/api/v3/role/%s/member", url.QueryEscape(id))
+	err := c.request("GET", path, nil, response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+func (c *Client) AddRoleMembers(id string, members ...RoleMember) error {
+	return c.updateRoleMembers(id, roleMemberUpdate{Add: members})
+}
+
+func (c *Client) RemoveRoleMembers(id string, members ...RoleMember) error {
+	return c.updateRoleMembers(id, roleMemberUpdate{Remove: members})
+}
+
+func (c *Client) AddUserToRole(roleId string, userId string) error {
+	return c.AddRoleMembers(roleId, RoleMember{Id: userId, Type: MemberTypeUser})
+}
+
+func (c *Client) RemoveUserFromRole(roleId string, userId string) error {
+	return c.RemoveRoleMembers(roleId, RoleMember{Id: userId, Type: MemberTypeUser})
+}
+
+func (c *Client) AddChildRole(roleId string, childRoleId string) error {
+	return c.AddRoleMembers(roleId, RoleMember{Id: childRoleId, Type: MemberTypeRole})
+}
+
+func (c *Client) RemoveChildRole(roleId string, childRoleId string) error {
+	return c.RemoveRoleMembers(roleId, RoleMember{Id: childRoleId, Type: MemberTypeRole})
+}
+
+func (c *Client) updateRoleMembers(id string, update roleMemberUpdate) error {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/api/v3/role/%s/member", url.QueryEscape(id))
+	return c.request("PATCH", path, bytes.NewBuffer(body), nil)
+}
@@ -0,0 +1,196 @@
+package dapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type TaggedEntity struct {
+	CatalogEntitySummary
+	Tags []string `json:"tags"`
+}
+
+type TagSearchStrategy int
+
+const (
+	// TagSearchWalk walks the catalog and reads the tags of every dataset.
+	// It always finds every tagged dataset but needs a request per dataset.
+	TagSearchWalk TagSearchStrategy = iota
+	// TagSearchEndpoint asks the dataset search endpoint of Dremio's
+	// internal apiv2 API for candidates and reads their tags. The endpoint
+	// is undocumented and not every version matches tags in it, in which
+	// case datasets are missed: only use it on deployments where it is known
+	// to work. Where the endpoint does not exist the catalog is walked.
+	TagSearchEndpoint
+)
+
+type TagSearchOptions struct {
+	Strategy TagSearchStrategy
+	// MatchAll requires a dataset to carry every tag instead of any of them.
+	MatchAll bool
+	// IncludeSources also walks sources when the catalog is walked. Walking
+	// a large source can be slow.
+	IncludeSources bool
+}
+
+func (o *TagSearchOptions) strategy() TagSearchStrategy {
+	if o == nil {
+		return TagSearchWalk
+	}
+	return o.Strategy
+}
+
+func (o *TagSearchOptions) matches(wanted []string, tags []string) bool {
+	matchAll := o != nil && o.MatchAll
+	for _, w := range wanted {
+		found := containsString(tags, w)
+		if found && !matchAll {
+			return true
+		}
+		if !found && matchAll {
+			return false
+		}
+	}
+	return matchAll
+}
+
+type datasetSearchHit struct {
+	FullPath    []string `json:"fullPath"`
+	DatasetType string   `json:"datasetType"`
+}
+
+// catalogDatasetType maps the dataset type reported by the search endpoint
+// to the one used by the catalog API.
+func (h *datasetSearchHit) catalogDatasetType() string {
+	if h.DatasetType == "VIRTUAL_DATASET" {
+		return "VIRTUAL"
+	}
+	return "PROMOTED"
+}
+
+// SearchByTags returns the datasets carrying the given tags, found with the
+// strategy chosen in opts. By default the catalog is walked.
+func (c *Client) SearchByTags(tags []string, opts *TagSearchOptions) ([]TaggedEntity, error) {
+	if len(tags) == 0 {
+		return nil, errors.New("At least one tag is required")
+	}
+	var result []TaggedEntity
+	var err error
+	switch opts.strategy() {
+	case TagSearchWalk:
+		result, err = c.searchTagsByWalk(tags, opts)
+	case TagSearchEndpoint:
+		result, err = c.searchTagsByEndpoint(tags, opts)
+		if isEndpointMissing(err) {
+			result, err = c.searchTagsByWalk(tags, opts)
+		}
+	default:
+		return nil, fmt.Errorf("unknown tag search strategy %d", opts.strategy())
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.Join(result[i].Path, ".") < strings.Join(result[j].Path, ".")
+	})
+	return result, nil
+}
+
+func isEndpointMissing(err error) bool {
+	var apiErr *ApiError
+	return errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusMethodNotAllowed)
+}
+
+// searchTagsByEndpoint uses GET /apiv2/datasets/search, part of the internal
+// API behind the Dremio UI rather than the documented v3 REST API.
+func (c *Client) searchTagsByEndpoint(tags []string, opts *TagSearchOptions) ([]TaggedEntity, error) {
+	var result []TaggedEntity
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		var hits []datasetSearchHit
+		path := fmt.Sprintf("/apiv2/datasets/search/?filter=%s", url.QueryEscape(tag))
+		if err := c.request("GET", path, nil, &hits); err != nil {
+			return nil, err
+		}
+		for _, hit := range hits {
+			key := strings.Join(hit.FullPath, "\x00")
+			if len(hit.FullPath) == 0 || seen[key] {
+				continue
+			}
+			seen[key] = true
+			entity, err := c.GetCatalogEntityByPath(hit.FullPath)
+			if IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			tagged, ok, err := c.taggedEntity(CatalogEntitySummary{
+				Id:          entity.Id,
+				Tag:         entity.Tag,
+				Path:        entity.Path,
+				Type:        "DATASET",
+				DatasetType: hit.catalogDatasetType(),
+			}, tags, opts)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				result = append(result, tagged)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) searchTagsByWalk(tags []string, opts *TagSearchOptions) ([]TaggedEntity, error) {
+	roots, err := c.GetRootCatalogSummary()
+	if err != nil {
+		return nil, err
+	}
+	var result []TaggedEntity
+	for _, root := range roots {
+		if root.ContainerType == "SOURCE" && (opts == nil || !opts.IncludeSources) {
+			continue
+		}
+		err := c.walkCatalog(root.Id, func(child CatalogChild) error {
+			if child.Type != "DATASET" {
+				return nil
+			}
+			tagged, ok, err := c.taggedEntity(CatalogEntitySummary{
+				Id:          child.Id,
+				Tag:         child.Tag,
+				Path:        child.Path,
+				Type:        child.Type,
+				DatasetType: child.DatasetType,
+			}, tags, opts)
+			if ok {
+				result = append(result, tagged)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) taggedEntity(summary CatalogEntitySummary, wanted []string, opts *TagSearchOptions) (TaggedEntity, bool, error) {
+	tags, err := c.GetEntityTags(summary.Id)
+	if IsNotFound(err) {
+		return TaggedEntity{}, false, nil
+	}
+	if err != nil {
+		return TaggedEntity{}, false, err
+	}
+	if !opts.matches(wanted, tags.Tags) {
+		return TaggedEntity{}, false, nil
+	}
+	return TaggedEntity{CatalogEntitySummary: summary, Tags: tags.Tags}, true, nil
+}
@@ -0,0 +1,26 @@
+package dapi
+
+import "testing"
+
+func TestTagSearchOptionsMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   *TagSearchOptions
+		wanted []string
+		tags   []string
+		match  bool
+	}{
+		{name: "any with nil options", wanted: []string{"a", "b"}, tags: []string{"b"}, match: true},
+		{name: "any without match", opts: &TagSearchOptions{}, wanted: []string{"a"}, tags: []string{"b"}},
+		{name: "all present", opts: &TagSearchOptions{MatchAll: true}, wanted: []string{"a", "b"}, tags: []string{"b", "c", "a"}, match: true},
+		{name: "all with one missing", opts: &TagSearchOptions{MatchAll: true}, wanted: []string{"a", "b"}, tags: []string{"a"}},
+		{name: "no tags", wanted: []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.matches(tt.wanted, tt.tags); got != tt.match {
+				t.Errorf("matches(%v, %v) = %v, want %v", tt.wanted, tt.tags, got, tt.match)
+			}
+		})
+	}
+}
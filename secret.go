@@ -0,0 +1,220 @@
+package dapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// ExistingSecretValue is the placeholder Dremio returns in place of stored
+// credentials. Sending it back on update keeps the stored value.
+const ExistingSecretValue = "$DREMIO_EXISTING_VALUE$"
+
+const redactedSecret = "******"
+
+// Secret holds a credential in a typed source configuration. It never prints
+// its value and is encoded as the plain value only when sent to Dremio.
+type Secret struct {
+	value    string
+	ref      string
+	resolved bool
+}
+
+func NewSecret(value string) *Secret {
+	return &Secret{value: value, resolved: true}
+}
+
+// ExistingSecret returns a secret that tells Dremio to keep the stored value.
+func ExistingSecret() *Secret {
+	return NewSecret(ExistingSecretValue)
+}
+
+// SecretRef returns a secret whose value is looked up by name from the
+// client's SecretProvider when the configuration is sent.
+func SecretRef(name string) *Secret {
+	return &Secret{ref: name}
+}
+
+func (s *Secret) Value() string {
+	return s.value
+}
+
+func (s *Secret) IsExisting() bool {
+	return s.value == ExistingSecretValue
+}
+
+func (s *Secret) isSet() bool {
+	return s != nil && (s.value != "" || s.ref != "")
+}
+
+func (s *Secret) String() string {
+	if s == nil {
+		return "<nil>"
+	}
+	if s.IsExisting() {
+		return ExistingSecretValue
+	}
+	return redactedSecret
+}
+
+func (s *Secret) Format(f fmt.State, verb rune) {
+	io.WriteString(f, s.String())
+}
+
+func (s *Secret) MarshalJSON() ([]byte, error) {
+	if !s.resolved {
+		return nil, fmt.Errorf("secret %s has not been resolved", s.ref)
+	}
+	return json.Marshal(s.value)
+}
+
+func (s *Secret) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = Secret{value: value, resolved: true}
+	return nil
+}
+
+func (s *Secret) resolve(provider SecretProvider) error {
+	if s.resolved {
+		return nil
+	}
+	if provider == nil {
+		return fmt.Errorf("secret %s requires a SecretProvider", s.ref)
+	}
+	value, err := provider.LookupSecret(s.ref)
+	if err != nil {
+		return err
+	}
+	s.value = value
+	s.resolved = true
+	return nil
+}
+
+// SecretProvider looks up secret values referenced with SecretRef.
+type SecretProvider interface {
+	LookupSecret(name string) (string, error)
+}
+
+// EnvSecretProvider reads secrets from environment variables named Prefix
+// followed by the secret name.
+type EnvSecretProvider struct {
+	Prefix string
+}
+
+func (p *EnvSecretProvider) LookupSecret(name string) (string, error) {
+	value, ok := os.LookupEnv(p.Prefix + name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s%s is not set", p.Prefix, name)
+	}
+	return value, nil
+}
+
+// FileSecretProvider reads secrets from files named after the secret in Dir,
+// as mounted by Docker and Kubernetes secrets. Trailing newlines are removed.
+type FileSecretProvider struct {
+	Dir string
+}
+
+func (p *FileSecretProvider) LookupSecret(name string) (string, error) {
+	contents, err := ioutil.ReadFile(filepath.Join(p.Dir, name))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(contents), "\r\n"), nil
+}
+
+var secretType = reflect.TypeOf(&Secret{})
+
+// resolveSecrets looks up the value of every unresolved Secret reachable
+// from v through exported fields, slices and pointers.
+func resolveSecrets(v interface{}, provider SecretProvider) error {
+	return resolveSecretValue(reflect.ValueOf(v), provider)
+}
+
+func resolveSecretValue(v reflect.Value, provider SecretProvider) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type() == secretType {
+			return v.Interface().(*Secret).resolve(provider)
+		}
+		return resolveSecretValue(v.Elem(), provider)
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return resolveSecretValue(v.Elem(), provider)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if err := resolveSecretValue(v.Field(i), provider); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveSecretValue(v.Index(i), provider); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var sensitiveKeys = []string{"password", "secret", "accesskey", "token", "privatekey"}
+
+// redactJson masks the values of credential-like keys in a JSON document so
+// that it can be logged. Documents that cannot be parsed are not logged.
+func redactJson(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "<unparseable body>"
+	}
+	redacted, err := json.Marshal(redactJsonValue(value))
+	if err != nil {
+		return "<unparseable body>"
+	}
+	return string(redacted)
+}
+
+func redactJsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if isSensitiveKey(k) {
+				if s, ok := child.(string); ok && s != "" && s != ExistingSecretValue {
+					v[k] = redactedSecret
+				}
+				continue
+			}
+			v[k] = redactJsonValue(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactJsonValue(child)
+		}
+	}
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, k := range sensitiveKeys {
+		if strings.HasSuffix(lower, k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package dapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedactJson(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "top level password",
+			body: `{"name":"u","password":"hunter2"}`,
+			want: `{"name":"u","password":"******"}`,
+		},
+		{
+			name: "nested config keys",
+			body: `{"config":{"hostname":"db","accessKey":"AKIA","accessSecret":"s","clientSecret":"c","authToken":"t"}}`,
+			want: `{"config":{"accessKey":"******","accessSecret":"******","authToken":"******","clientSecret":"******","hostname":"db"}}`,
+		},
+		{
+			name: "inside lists",
+			body: `{"items":[{"password":"p"},{"name":"n"}]}`,
+			want: `{"items":[{"password":"******"},{"name":"n"}]}`,
+		},
+		{
+			name: "existing and empty values kept",
+			body: `{"password":"$DREMIO_EXISTING_VALUE$","secret":""}`,
+			want: `{"password":"$DREMIO_EXISTING_VALUE$","secret":""}`,
+		},
+		{
+			name: "non string values kept",
+			body: `{"useToken":true,"tokenTtl":5}`,
+			want: `{"tokenTtl":5,"useToken":true}`,
+		},
+		{
+			name: "unparseable",
+			body: `password=hunter2`,
+			want: `<unparseable body>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactJson([]byte(tt.body)); got != tt.want {
+				t.Errorf("redactJson(%s) = %s, want %s", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretFormatting(t *testing.T) {
+	secret := NewSecret("hunter2")
+	config := struct{ Password *Secret }{secret}
+	for _, s := range []string{
+		fmt.Sprint(secret),
+		fmt.Sprintf("%v %+v %#v %s", config, config, config, secret),
+	} {
+		if strings.Contains(s, "hunter2") {
+			t.Errorf("formatted secret leaks its value: %s", s)
+		}
+	}
+	raw, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != `{"Password":"hunter2"}` {
+		t.Errorf("Marshal() = %s", raw)
+	}
+	if _, err := json.Marshal(struct{ Password *Secret }{SecretRef("db")}); err == nil {
+		t.Error("Marshal() of an unresolved secret reference succeeded")
+	}
+}
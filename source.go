@@ -2,14 +2,25 @@ package dapi
 
 import (
 	"errors"
+	"fmt"
 )
 
 type SourceMetadataPolicy struct {
-	AuthTTLMs             int    `json:"authTTLMs,omitempty"`
-	DatasetRefreshAfterMs int    `json:"datasetRefreshAfterMs,omitempty"`
-	DatasetExpireAfterMs  int    `json:"datasetExpireAfterMs,omitempty"`
-	NamesRefreshMs        int    `json:"namesRefreshMs,omitempty"`
-	DatasetUpdateMode     string `json:"datasetUpdateMode,omitempty"`
+	AuthTTLMs             int64             `json:"authTTLMs,omitempty"`
+	DatasetRefreshAfterMs int64             `json:"datasetRefreshAfterMs,omitempty"`
+	DatasetExpireAfterMs  int64             `json:"datasetExpireAfterMs,omitempty"`
+	NamesRefreshMs        int64             `json:"namesRefreshMs,omitempty"`
+	DatasetUpdateMode     DatasetUpdateMode `json:"datasetUpdateMode,omitempty"`
+}
+
+// SourceMetadataPolicyPatch holds the metadata policy settings to change.
+// Nil fields keep their current value.
+type SourceMetadataPolicyPatch struct {
+	AuthTTLMs             *int64             `json:"authTTLMs,omitempty"`
+	DatasetRefreshAfterMs *int64             `json:"datasetRefreshAfterMs,omitempty"`
+	DatasetExpireAfterMs  *int64             `json:"datasetExpireAfterMs,omitempty"`
+	NamesRefreshMs        *int64             `json:"namesRefreshMs,omitempty"`
+	DatasetUpdateMode     *DatasetUpdateMode `json:"datasetUpdateMode,omitempty"`
 }
 
 type Source struct {
@@ -17,12 +28,43 @@ type Source struct {
 	Description                 string                `json:"description,omitempty"`
 	Type                        string                `json:"type,omitempty"`
 	Config                      interface{}           `json:"config,omitempty"`
-	CreatedAt                   string                `json:"createdAt,omitempty"`
+	CreatedAt                   *Timestamp            `json:"createdAt,omitempty"`
 	MetadataPolicy              *SourceMetadataPolicy `json:"metadataPolicy,omitempty"`
-	AccelerationRefreshPeriodMs int                   `json:"accelerationRefreshPeriodMs,omitempty"`
-	AccelerationGracePeriodMs   int                   `json:"accelerationGracePeriodMs,omitempty"`
+	AccelerationRefreshPeriodMs int64                 `json:"accelerationRefreshPeriodMs,omitempty"`
+	AccelerationGracePeriodMs   int64                 `json:"accelerationGracePeriodMs,omitempty"`
 	AccelerationNeverExpire     bool                  `json:"accelerationNeverExpire,omitempty"`
 	AccelerationNeverRefresh    bool                  `json:"accelerationNeverRefresh,omitempty"`
+	State                       *SourceState          `json:"state,omitempty"`
+}
+
+// TypedConfig decodes Config into the typed configuration registered for the
+// source type. It returns nil if no configuration is registered for the type.
+// Config itself keeps the generic value returned by the API, including any
+// settings the typed configuration does not model.
+func (s *Source) TypedConfig() (SourceConfig, error) {
+	if config, ok := s.Config.(SourceConfig); ok {
+		return config, nil
+	}
+	if s.Config == nil {
+		return nil, nil
+	}
+	return DecodeSourceConfig(s.Type, s.Config)
+}
+
+// prepareSourceConfig resolves secret references in a typed source
+// configuration and validates it before it is sent.
+func (c *Client) prepareSourceConfig(sourceType string, config interface{}) error {
+	if err := resolveSecrets(config, c.config.SecretProvider); err != nil {
+		return err
+	}
+	typed, ok := config.(SourceConfig)
+	if !ok {
+		return nil
+	}
+	if typed.SourceType() != sourceType {
+		return fmt.Errorf("Source config is for %s but source type is %s", typed.SourceType(), sourceType)
+	}
+	return typed.Validate()
 }
 
 func (c *Client) GetSource(id string) (*Source, error) {
@@ -44,14 +86,14 @@ type NewSourceSpec struct {
 	Type                        string
 	Config                      interface{}
 	MetadataPolicy              *SourceMetadataPolicy
-	AccelerationRefreshPeriodMs int
-	AccelerationGracePeriodMs   int
+	AccelerationRefreshPeriodMs int64
+	AccelerationGracePeriodMs   int64
 	AccelerationNeverExpire     bool
 	AccelerationNeverRefresh    bool
 }
 
-func (c *Client) NewSource(spec *NewSourceSpec) (*Source, error) {
-	source := Source{
+func (spec *NewSourceSpec) source() Source {
+	return Source{
 		CatalogEntity: CatalogEntity{
 			EntityType: "source",
 			Name:       spec.Name,
@@ -65,6 +107,19 @@ func (c *Client) NewSource(spec *NewSourceSpec) (*Source, error) {
 		AccelerationNeverExpire:     spec.AccelerationNeverExpire,
 		AccelerationNeverRefresh:    spec.AccelerationNeverRefresh,
 	}
+}
+
+func (c *Client) NewSource(spec *NewSourceSpec) (*Source, error) {
+	source := spec.source()
+	if config, ok := spec.Config.(SourceConfig); ok && source.Type == "" {
+		source.Type = config.SourceType()
+	}
+	if err := c.prepareSourceConfig(source.Type, source.Config); err != nil {
+		return nil, err
+	}
+	if err := validateSourcePolicies(&source); err != nil {
+		return nil, err
+	}
 	result := new(Source)
 	err := c.newCatalogItem(source, result)
 	if err != nil {
@@ -75,11 +130,12 @@ func (c *Client) NewSource(spec *NewSourceSpec) (*Source, error) {
 }
 
 type UpdateSourceSpec struct {
+	Tag                         string
 	Description                 string
 	Config                      interface{}
 	MetadataPolicy              *SourceMetadataPolicy
-	AccelerationRefreshPeriodMs int
-	AccelerationGracePeriodMs   int
+	AccelerationRefreshPeriodMs int64
+	AccelerationGracePeriodMs   int64
 	AccelerationNeverExpire     bool
 	AccelerationNeverRefresh    bool
 }
@@ -89,6 +145,9 @@ func (c *Client) UpdateSource(id string, spec *UpdateSourceSpec) (*Source, error
 	if err != nil {
 		return nil, err
 	}
+	if err := checkTag(id, spec.Tag, original.Tag); err != nil {
+		return nil, err
+	}
 	source := Source{
 		CatalogEntity:               original.CatalogEntity,
 		Type:                        original.Type,
@@ -100,11 +159,99 @@ func (c *Client) UpdateSource(id string, spec *UpdateSourceSpec) (*Source, error
 		AccelerationNeverExpire:     spec.AccelerationNeverExpire,
 		AccelerationNeverRefresh:    spec.AccelerationNeverRefresh,
 	}
+	source.Tag = writeTag(spec.Tag, original.Tag)
+	if err := c.prepareSourceConfig(source.Type, source.Config); err != nil {
+		return nil, err
+	}
+	if err := validateSourcePolicies(&source); err != nil {
+		return nil, err
+	}
 	result := new(Source)
 	err = c.updateCatalogItem(id, source, result)
 	if err != nil {
 		return nil, err
 	}
 	result.EnrichFields()
-	return result, err
+	return result, nil
+}
+
+// PatchSourceSpec describes a partial source update. Nil fields keep their
+// current value; Config and MetadataPolicy are merged key by key into the
+// current values.
+//
+// Config is merged from its JSON encoding. The typed configurations omit
+// false, zero and empty values, so they can only set options; to set an
+// option back to false, 0 or "" pass a map[string]interface{} holding the
+// key instead.
+type PatchSourceSpec struct {
+	Tag                         string
+	Description                 *string
+	Config                      interface{}
+	MetadataPolicy              *SourceMetadataPolicyPatch
+	AccelerationRefreshPeriodMs *int64
+	AccelerationGracePeriodMs   *int64
+	AccelerationNeverExpire     *bool
+	AccelerationNeverRefresh    *bool
+}
+
+func (c *Client) PatchSource(id string, spec *PatchSourceSpec) (*Source, error) {
+	original, err := c.GetSource(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTag(id, spec.Tag, original.Tag); err != nil {
+		return nil, err
+	}
+	source := *original
+	source.Tag = writeTag(spec.Tag, original.Tag)
+	source.State = nil
+	if spec.Description != nil {
+		source.Description = *spec.Description
+	}
+	if spec.Config != nil {
+		if err := resolveSecrets(spec.Config, c.config.SecretProvider); err != nil {
+			return nil, err
+		}
+		config, err := mergeJson(source.Config, spec.Config)
+		if err != nil {
+			return nil, err
+		}
+		typed, err := DecodeSourceConfig(source.Type, config)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.prepareSourceConfig(source.Type, typed); err != nil {
+			return nil, err
+		}
+		source.Config = config
+	}
+	if spec.MetadataPolicy != nil {
+		policy := new(SourceMetadataPolicy)
+		if err := mergeJsonInto(policy, original.MetadataPolicy, spec.MetadataPolicy); err != nil {
+			return nil, err
+		}
+		source.MetadataPolicy = policy
+	}
+	if spec.AccelerationRefreshPeriodMs != nil {
+		source.AccelerationRefreshPeriodMs = *spec.AccelerationRefreshPeriodMs
+	}
+	if spec.AccelerationGracePeriodMs != nil {
+		source.AccelerationGracePeriodMs = *spec.AccelerationGracePeriodMs
+	}
+	if spec.AccelerationNeverExpire != nil {
+		source.AccelerationNeverExpire = *spec.AccelerationNeverExpire
+	}
+	if spec.AccelerationNeverRefresh != nil {
+		source.AccelerationNeverRefresh = *spec.AccelerationNeverRefresh
+	}
+	if err := validateSourcePolicies(&source); err != nil {
+		return nil, err
+	}
+	result := new(Source)
+	err = c.updateCatalogItem(id, source, result)
+	if err != nil {
+		return nil, err
+	}
+	result.EnrichFields()
+	return result, nil
 }
@@ -0,0 +1,52 @@
+package dapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSourceTypedConfig(t *testing.T) {
+	source := new(Source)
+	body := `{"entityType": "source", "type": "POSTGRES", "config": {"hostname": "db", "port": "5432", "useLegacyDialect": true}}`
+	if err := json.Unmarshal([]byte(body), source); err != nil {
+		t.Fatal(err)
+	}
+	config, ok := source.Config.(map[string]interface{})
+	if !ok || config["useLegacyDialect"] != true {
+		t.Errorf("Config = %#v, want the generic config with every key", source.Config)
+	}
+	typed, err := source.TypedConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	postgres, ok := typed.(*PostgresConfig)
+	if !ok || postgres.Hostname != "db" || postgres.Port != "5432" {
+		t.Errorf("TypedConfig() = %#v, want *PostgresConfig for db:5432", typed)
+	}
+
+	source.Type = "UNREGISTERED"
+	typed, err = source.TypedConfig()
+	if err != nil || typed != nil {
+		t.Errorf("TypedConfig() = %#v, %v, want nil for an unregistered type", typed, err)
+	}
+}
+
+func TestSourceConfigDefaultTrueOptions(t *testing.T) {
+	raw, err := json.Marshal(&S3Config{EnableAsync: Bool(false), IsCachingEnabled: Bool(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"enableAsync":false,"isCachingEnabled":true}`; string(raw) != want {
+		t.Errorf("Marshal() = %s, want %s", raw, want)
+	}
+}
+
+func TestHive3ConfigValidate(t *testing.T) {
+	err := (&Hive3Config{}).Validate()
+	if err == nil || err.Error() != "HIVE3 source config requires hostname" {
+		t.Errorf("Validate() = %v, want the HIVE3 hostname error", err)
+	}
+	if err := (&Hive3Config{HiveConfig{Hostname: "metastore"}}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
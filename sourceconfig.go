@@ -0,0 +1,526 @@
+package dapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// SourceConfig is implemented by the typed configuration of each source
+// connector. Values of these types can be used as Source.Config and
+// NewSourceSpec.Config.
+//
+// Options that Dremio enables by default are *bool, so that they can be
+// turned off with Bool(false); nil leaves the server default in place.
+type SourceConfig interface {
+	SourceType() string
+	Validate() error
+}
+
+var (
+	sourceConfigMu    sync.RWMutex
+	sourceConfigTypes = map[string]func() SourceConfig{}
+)
+
+// RegisterSourceConfig makes the typed configuration returned by factory
+// available for sources of the given type, replacing any earlier
+// registration.
+func RegisterSourceConfig(sourceType string, factory func() SourceConfig) {
+	sourceConfigMu.Lock()
+	defer sourceConfigMu.Unlock()
+	sourceConfigTypes[sourceType] = factory
+}
+
+// DecodeSourceConfig converts a generic source configuration, as decoded from
+// the API, into the typed configuration registered for sourceType. It returns
+// nil if no configuration is registered for the type.
+func DecodeSourceConfig(sourceType string, config interface{}) (SourceConfig, error) {
+	sourceConfigMu.RLock()
+	factory, ok := sourceConfigTypes[sourceType]
+	sourceConfigMu.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+	result := factory()
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func init() {
+	RegisterSourceConfig("S3", func() SourceConfig { return new(S3Config) })
+	RegisterSourceConfig("NAS", func() SourceConfig { return new(NASConfig) })
+	RegisterSourceConfig("HDFS", func() SourceConfig { return new(HDFSConfig) })
+	RegisterSourceConfig("POSTGRES", func() SourceConfig { return new(PostgresConfig) })
+	RegisterSourceConfig("MYSQL", func() SourceConfig { return new(MySQLConfig) })
+	RegisterSourceConfig("ORACLE", func() SourceConfig { return new(OracleConfig) })
+	RegisterSourceConfig("MSSQL", func() SourceConfig { return new(MSSQLConfig) })
+	RegisterSourceConfig("REDSHIFT", func() SourceConfig { return new(RedshiftConfig) })
+	RegisterSourceConfig("ELASTIC", func() SourceConfig { return new(ElasticConfig) })
+	RegisterSourceConfig("MONGO", func() SourceConfig { return new(MongoConfig) })
+	RegisterSourceConfig("HIVE", func() SourceConfig { return new(HiveConfig) })
+	RegisterSourceConfig("HIVE3", func() SourceConfig { return new(Hive3Config) })
+	RegisterSourceConfig("NESSIE", func() SourceConfig { return new(NessieConfig) })
+	RegisterSourceConfig("AZURE_STORAGE", func() SourceConfig { return new(AzureStorageConfig) })
+	RegisterSourceConfig("GCS", func() SourceConfig { return new(GCSConfig) })
+}
+
+const (
+	AuthenticationTypeAnonymous = "ANONYMOUS"
+	AuthenticationTypeMaster    = "MASTER"
+)
+
+type SourceProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type SourceHost struct {
+	Hostname string `json:"hostname"`
+	Port     int    `json:"port,omitempty"`
+}
+
+func requireConfig(sourceType string, field string, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s source config requires %s", sourceType, field)
+	}
+	return nil
+}
+
+func requireSecret(sourceType string, field string, value *Secret) error {
+	if !value.isSet() {
+		return fmt.Errorf("%s source config requires %s", sourceType, field)
+	}
+	return nil
+}
+
+func requireCredentials(sourceType string, authenticationType string, username string, password *Secret) error {
+	if authenticationType != AuthenticationTypeMaster {
+		return nil
+	}
+	if err := requireConfig(sourceType, "username", username); err != nil {
+		return err
+	}
+	return requireSecret(sourceType, "password", password)
+}
+
+func requireHosts(sourceType string, hosts []SourceHost) error {
+	if len(hosts) == 0 {
+		return fmt.Errorf("%s source config requires hostList", sourceType)
+	}
+	for _, h := range hosts {
+		if err := requireConfig(sourceType, "hostList hostname", h.Hostname); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type S3Config struct {
+	CredentialType        string           `json:"credentialType,omitempty"`
+	AccessKey             string           `json:"accessKey,omitempty"`
+	AccessSecret          *Secret          `json:"accessSecret,omitempty"`
+	AssumedRoleARN        string           `json:"assumedRoleARN,omitempty"`
+	AwsProfile            string           `json:"awsProfile,omitempty"`
+	Secure                *bool            `json:"secure,omitempty"`
+	ExternalBucketList    []string         `json:"externalBucketList,omitempty"`
+	WhitelistedBuckets    []string         `json:"whitelistedBuckets,omitempty"`
+	RootPath              string           `json:"rootPath,omitempty"`
+	EnableAsync           *bool            `json:"enableAsync,omitempty"`
+	CompatibilityMode     bool             `json:"compatibilityMode,omitempty"`
+	RequesterPays         bool             `json:"requesterPays,omitempty"`
+	EnableFileStatusCheck *bool            `json:"enableFileStatusCheck,omitempty"`
+	IsCachingEnabled      *bool            `json:"isCachingEnabled,omitempty"`
+	MaxCacheSpacePct      int              `json:"maxCacheSpacePct,omitempty"`
+	DefaultCtasFormat     string           `json:"defaultCtasFormat,omitempty"`
+	PropertyList          []SourceProperty `json:"propertyList,omitempty"`
+}
+
+func (s *S3Config) SourceType() string {
+	return "S3"
+}
+
+func (s *S3Config) Validate() error {
+	switch s.CredentialType {
+	case "ACCESS_KEY":
+		if err := requireConfig("S3", "accessKey", s.AccessKey); err != nil {
+			return err
+		}
+		return requireSecret("S3", "accessSecret", s.AccessSecret)
+	case "AWS_PROFILE":
+		return requireConfig("S3", "awsProfile", s.AwsProfile)
+	case "", "EC2_METADATA", "NONE":
+		return nil
+	default:
+		return fmt.Errorf("S3 source config has unknown credentialType %s", s.CredentialType)
+	}
+}
+
+type NASConfig struct {
+	Path              string           `json:"path,omitempty"`
+	DefaultCtasFormat string           `json:"defaultCtasFormat,omitempty"`
+	PropertyList      []SourceProperty `json:"propertyList,omitempty"`
+}
+
+func (s *NASConfig) SourceType() string {
+	return "NAS"
+}
+
+func (s *NASConfig) Validate() error {
+	return requireConfig("NAS", "path", s.Path)
+}
+
+type HDFSConfig struct {
+	Hostname               string           `json:"hostname,omitempty"`
+	Port                   int              `json:"port,omitempty"`
+	EnableImpersonation    bool             `json:"enableImpersonation,omitempty"`
+	RootPath               string           `json:"rootPath,omitempty"`
+	ShortCircuitFlag       string           `json:"shortCircuitFlag,omitempty"`
+	ShortCircuitSocketPath string           `json:"shortCircuitSocketPath,omitempty"`
+	PropertyList           []SourceProperty `json:"propertyList,omitempty"`
+}
+
+func (s *HDFSConfig) SourceType() string {
+	return "HDFS"
+}
+
+func (s *HDFSConfig) Validate() error {
+	return requireConfig("HDFS", "hostname", s.Hostname)
+}
+
+type PostgresConfig struct {
+	Hostname                 string  `json:"hostname,omitempty"`
+	Port                     string  `json:"port,omitempty"`
+	DatabaseName             string  `json:"databaseName,omitempty"`
+	AuthenticationType       string  `json:"authenticationType,omitempty"`
+	Username                 string  `json:"username,omitempty"`
+	Password                 *Secret `json:"password,omitempty"`
+	UseSsl                   bool    `json:"useSsl,omitempty"`
+	EncryptionValidationMode string  `json:"encryptionValidationMode,omitempty"`
+	FetchSize                int     `json:"fetchSize,omitempty"`
+	MaxIdleConns             int     `json:"maxIdleConns,omitempty"`
+	IdleTimeSec              int     `json:"idleTimeSec,omitempty"`
+	QueryTimeoutSec          int     `json:"queryTimeoutSec,omitempty"`
+}
+
+func (s *PostgresConfig) SourceType() string {
+	return "POSTGRES"
+}
+
+func (s *PostgresConfig) Validate() error {
+	if err := requireConfig("POSTGRES", "hostname", s.Hostname); err != nil {
+		return err
+	}
+	if err := requireConfig("POSTGRES", "databaseName", s.DatabaseName); err != nil {
+		return err
+	}
+	return requireCredentials("POSTGRES", s.AuthenticationType, s.Username, s.Password)
+}
+
+type MySQLConfig struct {
+	Hostname           string  `json:"hostname,omitempty"`
+	Port               string  `json:"port,omitempty"`
+	AuthenticationType string  `json:"authenticationType,omitempty"`
+	Username           string  `json:"username,omitempty"`
+	Password           *Secret `json:"password,omitempty"`
+	NetWriteTimeout    int     `json:"netWriteTimeout,omitempty"`
+	FetchSize          int     `json:"fetchSize,omitempty"`
+	MaxIdleConns       int     `json:"maxIdleConns,omitempty"`
+	IdleTimeSec        int     `json:"idleTimeSec,omitempty"`
+	QueryTimeoutSec    int     `json:"queryTimeoutSec,omitempty"`
+}
+
+func (s *MySQLConfig) SourceType() string {
+	return "MYSQL"
+}
+
+func (s *MySQLConfig) Validate() error {
+	if err := requireConfig("MYSQL", "hostname", s.Hostname); err != nil {
+		return err
+	}
+	return requireCredentials("MYSQL", s.AuthenticationType, s.Username, s.Password)
+}
+
+type OracleConfig struct {
+	Hostname            string  `json:"hostname,omitempty"`
+	Port                string  `json:"port,omitempty"`
+	Instance            string  `json:"instance,omitempty"`
+	AuthenticationType  string  `json:"authenticationType,omitempty"`
+	Username            string  `json:"username,omitempty"`
+	Password            *Secret `json:"password,omitempty"`
+	UseSsl              bool    `json:"useSsl,omitempty"`
+	SslServerCertDN     string  `json:"sslServerCertDN,omitempty"`
+	UseTimezoneAsRegion *bool   `json:"useTimezoneAsRegion,omitempty"`
+	IncludeSynonyms     bool    `json:"includeSynonyms,omitempty"`
+	FetchSize           int     `json:"fetchSize,omitempty"`
+	MaxIdleConns        int     `json:"maxIdleConns,omitempty"`
+	IdleTimeSec         int     `json:"idleTimeSec,omitempty"`
+	QueryTimeoutSec     int     `json:"queryTimeoutSec,omitempty"`
+}
+
+func (s *OracleConfig) SourceType() string {
+	return "ORACLE"
+}
+
+func (s *OracleConfig) Validate() error {
+	if err := requireConfig("ORACLE", "hostname", s.Hostname); err != nil {
+		return err
+	}
+	if err := requireConfig("ORACLE", "instance", s.Instance); err != nil {
+		return err
+	}
+	return requireCredentials("ORACLE", s.AuthenticationType, s.Username, s.Password)
+}
+
+type MSSQLConfig struct {
+	Hostname                 string  `json:"hostname,omitempty"`
+	Port                     string  `json:"port,omitempty"`
+	Database                 string  `json:"database,omitempty"`
+	AuthenticationType       string  `json:"authenticationType,omitempty"`
+	Username                 string  `json:"username,omitempty"`
+	Password                 *Secret `json:"password,omitempty"`
+	UseSsl                   bool    `json:"useSsl,omitempty"`
+	EnableServerVerification *bool   `json:"enableServerVerification,omitempty"`
+	HostnameOverride         string  `json:"hostnameOverride,omitempty"`
+	FetchSize                int     `json:"fetchSize,omitempty"`
+	MaxIdleConns             int     `json:"maxIdleConns,omitempty"`
+	IdleTimeSec              int     `json:"idleTimeSec,omitempty"`
+	QueryTimeoutSec          int     `json:"queryTimeoutSec,omitempty"`
+}
+
+func (s *MSSQLConfig) SourceType() string {
+	return "MSSQL"
+}
+
+func (s *MSSQLConfig) Validate() error {
+	if err := requireConfig("MSSQL", "hostname", s.Hostname); err != nil {
+		return err
+	}
+	return requireCredentials("MSSQL", s.AuthenticationType, s.Username, s.Password)
+}
+
+type RedshiftConfig struct {
+	ConnectionString   string  `json:"connectionString,omitempty"`
+	AuthenticationType string  `json:"authenticationType,omitempty"`
+	Username           string  `json:"username,omitempty"`
+	Password           *Secret `json:"password,omitempty"`
+	FetchSize          int     `json:"fetchSize,omitempty"`
+	MaxIdleConns       int     `json:"maxIdleConns,omitempty"`
+	IdleTimeSec        int     `json:"idleTimeSec,omitempty"`
+	QueryTimeoutSec    int     `json:"queryTimeoutSec,omitempty"`
+}
+
+func (s *RedshiftConfig) SourceType() string {
+	return "REDSHIFT"
+}
+
+func (s *RedshiftConfig) Validate() error {
+	if err := requireConfig("REDSHIFT", "connectionString", s.ConnectionString); err != nil {
+		return err
+	}
+	return requireCredentials("REDSHIFT", s.AuthenticationType, s.Username, s.Password)
+}
+
+type ElasticConfig struct {
+	HostList                                  []SourceHost `json:"hostList,omitempty"`
+	AuthenticationType                        string       `json:"authenticationType,omitempty"`
+	Username                                  string       `json:"username,omitempty"`
+	Password                                  *Secret      `json:"password,omitempty"`
+	SslEnabled                                bool         `json:"sslEnabled,omitempty"`
+	ShowHiddenIndices                         bool         `json:"showHiddenIndices,omitempty"`
+	ShowIdColumn                              bool         `json:"showIdColumn,omitempty"`
+	ScriptsEnabled                            *bool        `json:"scriptsEnabled,omitempty"`
+	UsePainless                               *bool        `json:"usePainless,omitempty"`
+	UseWhitelist                              bool         `json:"useWhitelist,omitempty"`
+	AllowPushdownOnNormalizedOrAnalyzedFields bool         `json:"allowPushdownOnNormalizedOrAnalyzedFields,omitempty"`
+	WarnOnRowCountMismatch                    bool         `json:"warnOnRowCountMismatch,omitempty"`
+	ReadTimeoutMillis                         int          `json:"readTimeoutMillis,omitempty"`
+	ScrollTimeoutMillis                       int          `json:"scrollTimeoutMillis,omitempty"`
+	ScrollSize                                int          `json:"scrollSize,omitempty"`
+}
+
+func (s *ElasticConfig) SourceType() string {
+	return "ELASTIC"
+}
+
+func (s *ElasticConfig) Validate() error {
+	if err := requireHosts("ELASTIC", s.HostList); err != nil {
+		return err
+	}
+	return requireCredentials("ELASTIC", s.AuthenticationType, s.Username, s.Password)
+}
+
+type MongoConfig struct {
+	HostList                    []SourceHost     `json:"hostList,omitempty"`
+	AuthenticationType          string           `json:"authenticationType,omitempty"`
+	Username                    string           `json:"username,omitempty"`
+	Password                    *Secret          `json:"password,omitempty"`
+	AuthDatabase                string           `json:"authDatabase,omitempty"`
+	UseSsl                      bool             `json:"useSsl,omitempty"`
+	SecondaryReadsOnly          bool             `json:"secondaryReadsOnly,omitempty"`
+	AuthenticationTimeoutMillis int              `json:"authenticationTimeoutMillis,omitempty"`
+	SubpartitionSize            int              `json:"subpartitionSize,omitempty"`
+	PropertyList                []SourceProperty `json:"propertyList,omitempty"`
+}
+
+func (s *MongoConfig) SourceType() string {
+	return "MONGO"
+}
+
+func (s *MongoConfig) Validate() error {
+	if err := requireHosts("MONGO", s.HostList); err != nil {
+		return err
+	}
+	return requireCredentials("MONGO", s.AuthenticationType, s.Username, s.Password)
+}
+
+type HiveConfig struct {
+	Hostname            string           `json:"hostname,omitempty"`
+	Port                int              `json:"port,omitempty"`
+	EnableSasl          bool             `json:"enableSasl,omitempty"`
+	KerberosPrincipal   string           `json:"kerberosPrincipal,omitempty"`
+	EnableImpersonation bool             `json:"enableImpersonation,omitempty"`
+	PropertyList        []SourceProperty `json:"propertyList,omitempty"`
+}
+
+func (s *HiveConfig) SourceType() string {
+	return "HIVE"
+}
+
+func (s *HiveConfig) Validate() error {
+	return s.validate("HIVE")
+}
+
+func (s *HiveConfig) validate(sourceType string) error {
+	if err := requireConfig(sourceType, "hostname", s.Hostname); err != nil {
+		return err
+	}
+	if s.EnableSasl {
+		return requireConfig(sourceType, "kerberosPrincipal", s.KerberosPrincipal)
+	}
+	return nil
+}
+
+type Hive3Config struct {
+	HiveConfig
+}
+
+func (s *Hive3Config) SourceType() string {
+	return "HIVE3"
+}
+
+func (s *Hive3Config) Validate() error {
+	return s.validate("HIVE3")
+}
+
+type NessieConfig struct {
+	NessieEndpoint    string           `json:"nessieEndpoint,omitempty"`
+	NessieAuthType    string           `json:"nessieAuthType,omitempty"`
+	NessieAccessToken *Secret          `json:"nessieAccessToken,omitempty"`
+	StorageProvider   string           `json:"storageProvider,omitempty"`
+	AwsRootPath       string           `json:"awsRootPath,omitempty"`
+	CredentialType    string           `json:"credentialType,omitempty"`
+	AwsAccessKey      string           `json:"awsAccessKey,omitempty"`
+	AwsAccessSecret   *Secret          `json:"awsAccessSecret,omitempty"`
+	AssumedRoleARN    string           `json:"assumedRoleARN,omitempty"`
+	Secure            *bool            `json:"secure,omitempty"`
+	PropertyList      []SourceProperty `json:"propertyList,omitempty"`
+}
+
+func (s *NessieConfig) SourceType() string {
+	return "NESSIE"
+}
+
+func (s *NessieConfig) Validate() error {
+	if err := requireConfig("NESSIE", "nessieEndpoint", s.NessieEndpoint); err != nil {
+		return err
+	}
+	if s.NessieAuthType == "BEARER" {
+		if err := requireSecret("NESSIE", "nessieAccessToken", s.NessieAccessToken); err != nil {
+			return err
+		}
+	}
+	if s.CredentialType == "ACCESS_KEY" {
+		if err := requireConfig("NESSIE", "awsAccessKey", s.AwsAccessKey); err != nil {
+			return err
+		}
+		return requireSecret("NESSIE", "awsAccessSecret", s.AwsAccessSecret)
+	}
+	return nil
+}
+
+type AzureStorageConfig struct {
+	AccountKind       string           `json:"accountKind,omitempty"`
+	AccountName       string           `json:"accountName,omitempty"`
+	CredentialsType   string           `json:"credentialsType,omitempty"`
+	AccessKey         *Secret          `json:"accessKey,omitempty"`
+	ClientId          string           `json:"clientId,omitempty"`
+	ClientSecret      *Secret          `json:"clientSecret,omitempty"`
+	TokenEndpoint     string           `json:"tokenEndpoint,omitempty"`
+	RootPath          string           `json:"rootPath,omitempty"`
+	EnableSSL         *bool            `json:"enableSSL,omitempty"`
+	ContainerList     []string         `json:"containers,omitempty"`
+	EnableAsync       *bool            `json:"enableAsync,omitempty"`
+	DefaultCtasFormat string           `json:"defaultCtasFormat,omitempty"`
+	PropertyList      []SourceProperty `json:"propertyList,omitempty"`
+}
+
+func (s *AzureStorageConfig) SourceType() string {
+	return "AZURE_STORAGE"
+}
+
+func (s *AzureStorageConfig) Validate() error {
+	if err := requireConfig("AZURE_STORAGE", "accountName", s.AccountName); err != nil {
+		return err
+	}
+	switch s.CredentialsType {
+	case "ACCESS_KEY":
+		return requireSecret("AZURE_STORAGE", "accessKey", s.AccessKey)
+	case "AZURE_ACTIVE_DIRECTORY":
+		if err := requireConfig("AZURE_STORAGE", "clientId", s.ClientId); err != nil {
+			return err
+		}
+		if err := requireSecret("AZURE_STORAGE", "clientSecret", s.ClientSecret); err != nil {
+			return err
+		}
+		return requireConfig("AZURE_STORAGE", "tokenEndpoint", s.TokenEndpoint)
+	}
+	return nil
+}
+
+type GCSConfig struct {
+	ProjectId         string           `json:"projectId,omitempty"`
+	AuthMode          string           `json:"authMode,omitempty"`
+	ClientEmail       string           `json:"clientEmail,omitempty"`
+	ClientId          string           `json:"clientId,omitempty"`
+	PrivateKeyId      string           `json:"privateKeyId,omitempty"`
+	PrivateKey        *Secret          `json:"privateKey,omitempty"`
+	RootPath          string           `json:"rootPath,omitempty"`
+	BucketWhitelist   []string         `json:"bucketWhitelist,omitempty"`
+	AsyncEnabled      *bool            `json:"asyncEnabled,omitempty"`
+	DefaultCtasFormat string           `json:"defaultCtasFormat,omitempty"`
+	PropertyList      []SourceProperty `json:"propertyList,omitempty"`
+}
+
+func (s *GCSConfig) SourceType() string {
+	return "GCS"
+}
+
+func (s *GCSConfig) Validate() error {
+	if err := requireConfig("GCS", "projectId", s.ProjectId); err != nil {
+		return err
+	}
+	if s.AuthMode == "SERVICE_ACCOUNT_KEYS" {
+		if err := requireConfig("GCS", "clientEmail", s.ClientEmail); err != nil {
+			return err
+		}
+		return requireSecret("GCS", "privateKey", s.PrivateKey)
+	}
+	return nil
+}
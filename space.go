@@ -6,7 +6,7 @@ import (
 
 type Space struct {
 	CatalogEntity
-	CreatedAt string                 `json:"createdAt,omitempty"`
+	CreatedAt *Timestamp             `json:"createdAt,omitempty"`
 	Children  []CatalogEntitySummary `json:"children,omitempty"`
 }
 
@@ -0,0 +1,145 @@
+package dapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+func (c *Client) setEntityTags(id string, tags []string, version string) (TagsBody, error) {
+	body, err := json.Marshal(TagsBody{Tags: tags, Version: version})
+	if err != nil {
+		return TagsBody{}, err
+	}
+	response := new(TagsBody)
+	url := fmt.Sprintf("/api/v3/catalog/%s/collaboration/tag", id)
+	err = c.request("POST", url, bytes.NewBuffer(body), response)
+	if isAlreadyExists(err) {
+		return TagsBody{}, &ConflictError{Id: id, ExpectedTag: version, Err: err}
+	}
+	if err != nil {
+		return TagsBody{}, err
+	}
+	return *response, nil
+}
+
+// modifyTags reads the current tags of an entity, applies modify and writes
+// the result using the version that was read, retrying when the tags were
+// changed concurrently.
+func (c *Client) modifyTags(id string, modify func([]string) []string) (TagsBody, error) {
+	var err error
+	for attempt := 0; attempt < maxModifyAttempts; attempt++ {
+		var current TagsBody
+		current, err = c.GetEntityTags(id)
+		if err != nil && !IsNotFound(err) {
+			return TagsBody{}, err
+		}
+		tags := modify(current.Tags)
+		if stringSlicesEqual(tags, current.Tags) {
+			return current, nil
+		}
+		var result TagsBody
+		result, err = c.setEntityTags(id, tags, current.Version)
+		if err == nil {
+			return result, nil
+		}
+		if !IsConflict(err) {
+			return TagsBody{}, err
+		}
+	}
+	return TagsBody{}, err
+}
+
+func (c *Client) AddTags(id string, tags ...string) (TagsBody, error) {
+	return c.modifyTags(id, func(current []string) []string {
+		return addTags(current, tags)
+	})
+}
+
+func (c *Client) RemoveTags(id string, tags ...string) (TagsBody, error) {
+	return c.modifyTags(id, func(current []string) []string {
+		return removeTags(current, tags)
+	})
+}
+
+func (c *Client) ReplaceTags(id string, tags []string) (TagsBody, error) {
+	return c.modifyTags(id, func(current []string) []string {
+		return addTags(nil, tags)
+	})
+}
+
+func addTags(current []string, added []string) []string {
+	result := append([]string{}, current...)
+	for _, t := range added {
+		if !containsString(result, t) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func removeTags(current []string, removed []string) []string {
+	result := []string{}
+	for _, t := range current {
+		if !containsString(removed, t) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// TagChange describes the tag edits for one entity. When Replace is not nil
+// it replaces the current tags before Add and Remove are applied.
+type TagChange struct {
+	Id      string
+	Replace []string
+	Add     []string
+	Remove  []string
+}
+
+type TagChangeResult struct {
+	Id   string
+	Tags TagsBody
+	Err  error
+}
+
+const defaultTagConcurrency = 4
+
+// BulkUpdateTags applies the tag changes using up to concurrency parallel
+// requests. Results are returned in the order of changes; a failed change
+// does not stop the others.
+func (c *Client) BulkUpdateTags(changes []TagChange, concurrency int) []TagChangeResult {
+	if concurrency <= 0 {
+		concurrency = defaultTagConcurrency
+	}
+	results := make([]TagChangeResult, len(changes))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, change := range changes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, change TagChange) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			tags, err := c.modifyTags(change.Id, func(current []string) []string {
+				if change.Replace != nil {
+					current = change.Replace
+				}
+				return removeTags(addTags(current, change.Add), change.Remove)
+			})
+			results[i] = TagChangeResult{Id: change.Id, Tags: tags, Err: err}
+		}(i, change)
+	}
+	wg.Wait()
+	return results
+}
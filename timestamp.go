@@ -0,0 +1,70 @@
+package dapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Timestamp decodes the timestamps returned by Dremio, which are either ISO
+// 8601 strings or epoch milliseconds. A decoded timestamp that has not been
+// changed is encoded exactly as it was received.
+type Timestamp struct {
+	time.Time
+	raw    []byte
+	parsed time.Time
+}
+
+func NewTimestamp(t time.Time) *Timestamp {
+	return &Timestamp{Time: t}
+}
+
+// AsTime returns the time, or the zero time if t is nil.
+func (t *Timestamp) AsTime() time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.Time
+}
+
+func (t *Timestamp) MarshalJSON() ([]byte, error) {
+	if len(t.raw) > 0 && t.Time.Equal(t.parsed) {
+		return t.raw, nil
+	}
+	return json.Marshal(t.Time.Format(time.RFC3339Nano))
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	parsed, err := parseTimestamp(data)
+	if err != nil {
+		return err
+	}
+	*t = Timestamp{
+		Time:   parsed,
+		raw:    append([]byte{}, data...),
+		parsed: parsed,
+	}
+	return nil
+}
+
+func parseTimestamp(data []byte) (time.Time, error) {
+	if bytes.Equal(data, []byte("null")) {
+		return time.Time{}, nil
+	}
+	var value string
+	if data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return time.Time{}, err
+		}
+	} else {
+		value = string(data)
+	}
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(0, ms*int64(time.Millisecond)).UTC(), nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
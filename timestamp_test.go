@@ -0,0 +1,92 @@
+package dapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want time.Time
+	}{
+		{name: "iso", json: `"2021-03-04T05:06:07.123Z"`, want: time.Date(2021, 3, 4, 5, 6, 7, 123000000, time.UTC)},
+		{name: "iso with offset", json: `"2021-03-04T07:06:07+02:00"`, want: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)},
+		{name: "epoch ms", json: `1614834367123`, want: time.Date(2021, 3, 4, 5, 6, 7, 123000000, time.UTC)},
+		{name: "quoted epoch ms", json: `"1614834367123"`, want: time.Date(2021, 3, 4, 5, 6, 7, 123000000, time.UTC)},
+		{name: "empty string", json: `""`},
+		{name: "null", json: `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ts Timestamp
+			if err := json.Unmarshal([]byte(tt.json), &ts); err != nil {
+				t.Fatal(err)
+			}
+			if !ts.Time.Equal(tt.want) {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.json, ts.Time, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimestampUnmarshalInvalid(t *testing.T) {
+	for _, data := range []string{`"yesterday"`, `true`, `"2021-13-01T00:00:00Z"`} {
+		var ts Timestamp
+		if err := json.Unmarshal([]byte(data), &ts); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want error", data, ts.Time)
+		}
+	}
+}
+
+func TestTimestampRoundTrip(t *testing.T) {
+	type wrapper struct {
+		At *Timestamp `json:"at,omitempty"`
+	}
+	for _, body := range []string{
+		`{"at":"2021-03-04T05:06:07.123Z"}`,
+		`{"at":1614834367123}`,
+		`{"at":"1614834367123"}`,
+		`{}`,
+	} {
+		var w wrapper
+		if err := json.Unmarshal([]byte(body), &w); err != nil {
+			t.Fatal(err)
+		}
+		got, err := json.Marshal(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != body {
+			t.Errorf("round trip of %s = %s", body, got)
+		}
+	}
+}
+
+func TestTimestampMarshalChanged(t *testing.T) {
+	var ts Timestamp
+	if err := json.Unmarshal([]byte(`1614834367123`), &ts); err != nil {
+		t.Fatal(err)
+	}
+	ts.Time = ts.Time.Add(time.Hour)
+	got, err := json.Marshal(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"2021-03-04T06:06:07.123Z"`; string(got) != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+	got, err = json.Marshal(NewTimestamp(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"2021-03-04T05:06:07Z"`; string(got) != want {
+		t.Errorf("Marshal(NewTimestamp) = %s, want %s", got, want)
+	}
+	var nilTs *Timestamp
+	if !nilTs.AsTime().IsZero() {
+		t.Error("AsTime() of nil is not the zero time")
+	}
+}
@@ -0,0 +1,181 @@
+package dapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type User struct {
+	Id        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	FirstName string          `json:"firstName,omitempty"`
+	LastName  string          `json:"lastName,omitempty"`
+	Email     string          `json:"email,omitempty"`
+	Tag       string          `json:"tag,omitempty"`
+	Roles     []RoleReference `json:"roles,omitempty"`
+}
+
+type userPayload struct {
+	User
+	Password string `json:"password,omitempty"`
+}
+
+// ExternalIdentityProviderError is returned when a user cannot be changed
+// because the deployment manages users through LDAP or another external
+// identity provider.
+type ExternalIdentityProviderError struct {
+	Err error
+}
+
+func (e *ExternalIdentityProviderError) Error() string {
+	return fmt.Sprintf("users are managed by an external identity provider: %v", e.Err)
+}
+
+func (e *ExternalIdentityProviderError) Unwrap() error {
+	return e.Err
+}
+
+// externalIdentityProviderMessages are phrases of the error messages Dremio
+// returns when a user change is refused because users come from an external
+// identity provider.
+var externalIdentityProviderMessages = []string{
+	"external identity provider",
+	"external authentication",
+	"managed by ldap",
+	"ldap users",
+}
+
+// asExternalIdentityProviderError wraps err in an
+// *ExternalIdentityProviderError when Dremio refused a user change because
+// user management is not available, and returns err unchanged otherwise.
+func asExternalIdentityProviderError(err error) error {
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	switch apiErr.StatusCode {
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return &ExternalIdentityProviderError{Err: err}
+	case http.StatusBadRequest, http.StatusForbidden:
+		message := strings.ToLower(apiErr.Message())
+		for _, m := range externalIdentityProviderMessages {
+			if strings.Contains(message, m) {
+				return &ExternalIdentityProviderError{Err: err}
+			}
+		}
+	}
+	return err
+}
+
+func (c *Client) GetUser(id string) (*User, error) {
+	response := new(User)
+	path := fmt.Sprintf("/api/v3/user/%s", url.QueryEscape(id))
+	err := c.request("GET", path, nil, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (c *Client) GetUserByName(name string) (*User, error) {
+	response := new(User)
+	path := fmt.Sprintf("/api/v3/user/by-name/%s", url.PathEscape(name))
+	err := c.request("GET", path, nil, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+type NewUserSpec struct {
+	Name      string
+	FirstName string
+	LastName  string
+	Email     string
+	Password  string
+}
+
+func (c *Client) NewUser(spec *NewUserSpec) (*User, error) {
+	user := userPayload{
+		User: User{
+			Name:      spec.Name,
+			FirstName: spec.FirstName,
+			LastName:  spec.LastName,
+			Email:     spec.Email,
+		},
+		Password: spec.Password,
+	}
+	body, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+	result := new(User)
+	err = c.request("POST", "/api/v3/user", bytes.NewBuffer(body), result)
+	if err != nil {
+		return nil, asExternalIdentityProviderError(err)
+	}
+	return result, nil
+}
+
+type UpdateUserSpec struct {
+	Tag       string
+	FirstName string
+	LastName  string
+	Email     string
+}
+
+func (c *Client) UpdateUser(id string, spec *UpdateUserSpec) (*User, error) {
+	original, err := c.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTag(id, spec.Tag, original.Tag); err != nil {
+		return nil, err
+	}
+	user := *original
+	user.Tag = writeTag(spec.Tag, original.Tag)
+	user.FirstName = spec.FirstName
+	user.LastName = spec.LastName
+	user.Email = spec.Email
+	return c.updateUser(id, userPayload{User: user})
+}
+
+// SetUserPassword sets the password of a local user.
+func (c *Client) SetUserPassword(id string, password string) (*User, error) {
+	original, err := c.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+	return c.updateUser(id, userPayload{User: *original, Password: password})
+}
+
+func (c *Client) updateUser(id string, user userPayload) (*User, error) {
+	body, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+	result := new(User)
+	path := fmt.Sprintf("/api/v3/user/%s", url.QueryEscape(id))
+	err = c.request("PUT", path, bytes.NewBuffer(body), result)
+	if isAlreadyExists(err) {
+		return nil, &ConflictError{Id: id, Err: err}
+	}
+	if err != nil {
+		return nil, asExternalIdentityProviderError(err)
+	}
+	return result, nil
+}
+
+func (c *Client) DeleteUser(id string) error {
+	user, err := c.GetUser(id)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/api/v3/user/%s?version=%s", url.QueryEscape(id), url.QueryEscape(user.Tag))
+	return asExternalIdentityProviderError(c.request("DELETE", path, nil, nil))
+}
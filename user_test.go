@@ -0,0 +1,47 @@
+package dapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAsExternalIdentityProviderError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		external bool
+	}{
+		{name: "not an api error", err: errors.New("connection refused")},
+		{name: "method not allowed", err: &ApiError{StatusCode: 405, Body: ""}, external: true},
+		{name: "not implemented", err: &ApiError{StatusCode: 501, Body: "{}"}, external: true},
+		{
+			name:     "ldap message",
+			err:      &ApiError{StatusCode: 400, Body: `{"errorMessage": "Users are managed by LDAP and cannot be created"}`},
+			external: true,
+		},
+		{
+			name: "validation error mentioning external",
+			err:  &ApiError{StatusCode: 400, Body: `{"errorMessage": "Invalid email: external@example.com"}`},
+		},
+		{
+			name: "phrase outside the message",
+			err:  &ApiError{StatusCode: 400, Body: `{"errorMessage": "Name is required", "moreInfo": "external authentication"}`},
+		},
+		{
+			name: "phrase with another status",
+			err:  &ApiError{StatusCode: 500, Body: `{"errorMessage": "external authentication"}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := asExternalIdentityProviderError(tt.err)
+			var external *ExternalIdentityProviderError
+			if errors.As(err, &external) != tt.external {
+				t.Errorf("asExternalIdentityProviderError(%v) = %v, want external %v", tt.err, err, tt.external)
+			}
+			if !tt.external && err != tt.err {
+				t.Errorf("asExternalIdentityProviderError(%v) = %v, want the original error", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package dapi
+
+import (
+	"context"
+	"time"
+)
+
+const defaultPollInterval = time.Second
+
+type WaitOptions struct {
+	PollInterval time.Duration
+}
+
+func (o *WaitOptions) pollInterval() time.Duration {
+	if o == nil || o.PollInterval <= 0 {
+		return defaultPollInterval
+	}
+	return o.PollInterval
+}
+
+// poll calls check every interval until it reports done, returns an error or
+// the context ends.
+func poll(ctx context.Context, interval time.Duration, check func() (bool, error)) error {
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}